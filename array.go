@@ -0,0 +1,240 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// NullElementPolicy decides how NULL elements of arrays are assigned to slices
+// whose elements can't hold NULL, like []int32.
+type NullElementPolicy int
+
+const (
+	// NullElementsZero assigns the zero value of the element type for NULL.
+	NullElementsZero NullElementPolicy = iota
+	// NullElementsError rejects arrays w/ NULL elements, ErrInvalidDestination is returned.
+	NullElementsError
+)
+
+// NullElements is the policy for NULL elements in slices of non-nullable types.
+// Slices of pointers and of Null types, like []*int32 or []Null[string], keep NULL elements as nil or invalid.
+var NullElements = NullElementsZero
+
+// NilSlices assigns NULL as nil to slice fields.
+// Empty arrays are always assigned as empty slices, which are not nil, so both can be told apart.
+// W/o NilSlices NULL can only be assigned to pointers, like *[]int32.
+var NilSlices = false
+
+// ReuseSlices reuses the backing array of the slice in a field if its capacity suffices.
+// This saves allocations when the same struct is filled again and again, e.g. in a loop over rows.
+// Slices handed out before are overwritten then, so they must not be kept.
+var ReuseSlices = false
+
+// sliceFor returns a slice w/ n elements to be filled and assigned to dest.
+// W/ ReuseSlices the slice in dest is reused if it is a []T w/ enough capacity.
+func sliceFor[T any](dest reflect.Value, n int) []T {
+	st := reflect.TypeOf([]T(nil))
+	if ReuseSlices && dest.Kind() == reflect.Slice && !dest.IsNil() && dest.Cap() >= n && dest.Type().AssignableTo(st) {
+		return dest.Convert(st).Interface().([]T)[:n]
+	}
+	return make([]T, n)
+}
+
+// makeSlice returns a slice of the type of dest w/ n zero elements to be filled and assigned to dest.
+// W/ ReuseSlices the slice in dest is reused if it has enough capacity.
+func makeSlice(dest reflect.Value, n int) reflect.Value {
+	if !ReuseSlices || dest.IsNil() || dest.Cap() < n {
+		return reflect.MakeSlice(dest.Type(), n, n)
+	}
+	s := dest.Slice(0, n)
+	zero := reflect.Zero(dest.Type().Elem())
+	for i := 0; i < n; i++ {
+		s.Index(i).Set(zero)
+	}
+	return s
+}
+
+var dimensionsType = reflect.TypeOf([]pgtype.ArrayDimension(nil))
+
+// arrayElements returns the elements and dimensions of v if it is a pgtype array, like pgtype.Int4Array.
+func arrayElements(v interface{}) (reflect.Value, []pgtype.ArrayDimension, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, nil, false
+	}
+	elems := rv.FieldByName("Elements")
+	dims := rv.FieldByName("Dimensions")
+	if elems.Kind() != reflect.Slice || !dims.IsValid() || dims.Type() != dimensionsType {
+		return reflect.Value{}, nil, false
+	}
+	return elems, dims.Interface().([]pgtype.ArrayDimension), true
+}
+
+// elementValue returns the value of the array element e like pgx returns it for a column, nil for NULL.
+func elementValue(e reflect.Value) (interface{}, error) {
+	g, ok := e.Interface().(interface{ Get() interface{} })
+	if !ok {
+		return nil, ErrInvalidDestination
+	}
+	return g.Get(), nil
+}
+
+// nullElementIndex returns the index of the first NULL element or -1.
+func nullElementIndex(elems reflect.Value) int {
+	for i := 0; i < elems.Len(); i++ {
+		if s := elems.Index(i).FieldByName("Status"); s.IsValid() && s.Interface() == pgtype.Null {
+			return i
+		}
+	}
+	return -1
+}
+
+// isNullableSlice checks for a slice whose elements can hold NULL: pointers, Null types and NullAssigners.
+func isNullableSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	e := v.Type().Elem()
+	if e.Kind() == reflect.Ptr {
+		return true
+	}
+	switch reflect.New(e).Interface().(type) {
+	case nullSetter, NullAssigner:
+		return true
+	}
+	return false
+}
+
+// assignElements assigns every element to a new slice for dest, like it would be assigned to a field.
+func assignElements(dest reflect.Value, elems reflect.Value, dims []pgtype.ArrayDimension, fd *pgproto3.FieldDescription) error {
+	if !isSimpleArray(dims, elems.Len()) {
+		return ErrNotSimpleSlice
+	}
+
+	efd := elementFD(fd)
+	res := makeSlice(dest, elems.Len())
+	for i := 0; i < elems.Len(); i++ {
+		v, err := elementValue(elems.Index(i))
+		if err != nil {
+			return err
+		}
+		v = normalizeValue(res.Index(i), v, efd.DataTypeOID)
+		err = assignValue(res.Index(i), v, efd)
+		if err != nil {
+			return err
+		}
+	}
+	dest.Set(res)
+	return nil
+}
+
+// assignFixedArray assigns the array v to the Go array dest, like [3]float64.
+// v is assigned to a slice of the element type first, its length has to match.
+func assignFixedArray(dest reflect.Value, v interface{}, fd *pgproto3.FieldDescription) error {
+	s := reflect.New(reflect.SliceOf(dest.Type().Elem())).Elem()
+	err := assignValue(s, v, fd)
+	if err != nil {
+		return err
+	}
+	if s.Len() != dest.Len() {
+		return fmt.Errorf("%w: array w/ %d elements for %s", ErrInvalidDestination, s.Len(), dest.Type())
+	}
+	reflect.Copy(dest, s)
+	return nil
+}
+
+// assignArray handles arrays for Go arrays, slices w/ nullable elements and json elements and applies NullElements.
+// It returns false if v is left to the special cases for arrays.
+func assignArray(dest reflect.Value, v interface{}, fd *pgproto3.FieldDescription) (bool, error) {
+	elems, dims, ok := arrayElements(v)
+	if !ok {
+		return false, nil
+	}
+	if dest.Kind() == reflect.Array {
+		return true, assignFixedArray(dest, v, fd)
+	}
+	if isNullableSlice(dest) {
+		return true, assignElements(dest, elems, dims, fd)
+	}
+	if NullElements == NullElementsError && dest.Kind() == reflect.Slice {
+		if i := nullElementIndex(elems); i >= 0 {
+			return true, fmt.Errorf("%w: NULL element at index %d", ErrInvalidDestination, i)
+		}
+	}
+	if dest.Kind() == reflect.Slice && isJSON(elementOID(fd.DataTypeOID)) {
+		// every element is unmarshaled, NULL ones are zero
+		return true, assignElements(dest, elems, dims, fd)
+	}
+	return false, nil
+}
+
+// Array holds an array w/ its dimensions, for arrays that aren't simple slices.
+//
+// Elements holds all elements in row-major order, like PostgreSQL stores them.
+// Dims holds the length and LowerBounds the lower bound of every dimension,
+// both are empty for an empty array.
+// The elements are assigned like to a field of type T, so e.g. Array[*int32] keeps NULL elements.
+// NULL can't be assigned to an Array, use *Array[T] for nullable columns.
+type Array[T any] struct {
+	Elements    []T
+	Dims        []int32
+	LowerBounds []int32
+}
+
+// arraySetter is implemented by all Array types.
+type arraySetter interface {
+	setArray(v interface{}, fd *pgproto3.FieldDescription) error
+}
+
+func (a *Array[T]) setArray(v interface{}, fd *pgproto3.FieldDescription) error {
+	var elems []interface{}
+	var dims []pgtype.ArrayDimension
+	switch v := v.(type) {
+	case nil:
+		return ErrInvalidDestination
+	case []interface{}:
+		// array w/o pgtype array, always one dimension
+		elems = v
+		if len(v) > 0 {
+			dims = []pgtype.ArrayDimension{{Length: int32(len(v)), LowerBound: 1}}
+		}
+	default:
+		ev, d, ok := arrayElements(v)
+		if !ok {
+			return ErrInvalidDestination
+		}
+		elems = make([]interface{}, ev.Len())
+		for i := range elems {
+			e, err := elementValue(ev.Index(i))
+			if err != nil {
+				return err
+			}
+			elems[i] = e
+		}
+		dims = d
+	}
+
+	na := Array[T]{
+		Elements:    make([]T, len(elems)),
+		Dims:        make([]int32, len(dims)),
+		LowerBounds: make([]int32, len(dims)),
+	}
+	efd := elementFD(fd)
+	for i, e := range elems {
+		ev := reflect.ValueOf(&na.Elements[i]).Elem()
+		err := assignValue(ev, normalizeValue(ev, e, efd.DataTypeOID), efd)
+		if err != nil {
+			return err
+		}
+	}
+	for i, d := range dims {
+		na.Dims[i] = d.Length
+		na.LowerBounds[i] = d.LowerBound
+	}
+
+	*a = na
+	return nil
+}
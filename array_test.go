@@ -0,0 +1,605 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// mkArray returns the value of the array type a set to src, as pgx returns it.
+func mkArray(a pgtype.Value, src interface{}) interface{} {
+	err := a.Set(src)
+	if err != nil {
+		panic(err)
+	}
+	return reflect.ValueOf(a).Elem().Interface()
+}
+
+func TestReadStructBoolSlice(t *testing.T) {
+	rows := mkColumnRows("flags", pgtype.BoolArrayOID, mkArray(&pgtype.BoolArray{}, []bool{true, false, true}))
+
+	var dest struct {
+		Flags []bool
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Flags, []bool{true, false, true}) {
+		t.Errorf("value mismatch for field Flags: %v", dest.Flags)
+	}
+
+	var destB struct {
+		Flags []int16
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err == nil {
+		t.Error("failed to detect invalid destination type")
+	}
+}
+
+func TestReadStructTimeSlice(t *testing.T) {
+	times := []time.Time{
+		time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	dates := []time.Time{
+		time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		oid  uint32
+		v    interface{}
+		want []time.Time
+	}{
+		{pgtype.TimestampArrayOID, mkArray(&pgtype.TimestampArray{}, times), times},
+		{pgtype.TimestamptzArrayOID, mkArray(&pgtype.TimestamptzArray{}, times), times},
+		{pgtype.DateArrayOID, mkArray(&pgtype.DateArray{}, dates), dates},
+	}
+	for _, tt := range tests {
+		var dest struct {
+			At []time.Time
+		}
+		err := pgxscan.ReadStruct(&dest, mkColumnRows("at", tt.oid, tt.v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dest.At) != len(tt.want) {
+			t.Fatalf("value mismatch for field At: %v", dest.At)
+		}
+		for i := range tt.want {
+			if !dest.At[i].Equal(tt.want[i]) {
+				t.Errorf("value mismatch for element %d: %v", i, dest.At[i])
+			}
+		}
+	}
+
+	inf := pgtype.DateArray{
+		Elements:   []pgtype.Date{{Status: pgtype.Present, InfinityModifier: pgtype.Infinity}},
+		Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	var dest struct {
+		At []time.Time
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("at", pgtype.DateArrayOID, inf))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("failed to detect infinite element: %v", err)
+	}
+}
+
+func TestReadStructUUIDSlice(t *testing.T) {
+	type uuid [16]byte
+
+	ids := [][16]byte{{1, 2, 3}, {15: 0xff}}
+	rows := mkColumnRows("ids", pgtype.UUIDArrayOID, mkArray(&pgtype.UUIDArray{}, ids))
+
+	var dest struct {
+		IDs []uuid
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []uuid{{1, 2, 3}, {15: 0xff}}) {
+		t.Errorf("value mismatch for field IDs: %v", dest.IDs)
+	}
+
+	var destB struct {
+		IDs []string
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err == nil {
+		t.Error("failed to detect invalid destination type")
+	}
+}
+
+func TestReadStructNullElements(t *testing.T) {
+	arr := pgtype.Int4Array{
+		Elements: []pgtype.Int4{
+			{Int: 1, Status: pgtype.Present},
+			{Status: pgtype.Null},
+			{Int: 3, Status: pgtype.Present},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 3, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := mkColumnRows("ids", pgtype.Int4ArrayOID, arr)
+
+	var dest struct {
+		IDs []int32
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []int32{1, 0, 3}) {
+		t.Errorf("value mismatch for field IDs: %v", dest.IDs)
+	}
+
+	pgxscan.NullElements = pgxscan.NullElementsError
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect NULL element: %v", err)
+	}
+
+	// nullable elements work w/ every policy
+	var destP struct {
+		IDs []*int32
+	}
+	err = pgxscan.ReadStruct(&destP, rows)
+	pgxscan.NullElements = pgxscan.NullElementsZero
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destP.IDs) != 3 || *destP.IDs[0] != 1 || destP.IDs[1] != nil || *destP.IDs[2] != 3 {
+		t.Errorf("value mismatch for field IDs: %v", destP.IDs)
+	}
+
+	var destN struct {
+		IDs []pgxscan.Null[int64]
+	}
+	err = pgxscan.ReadStruct(&destN, rows)
+	if err == nil {
+		t.Error("failed to detect invalid element type")
+	}
+
+	var destM struct {
+		IDs []pgxscan.Null[int32]
+	}
+	err = pgxscan.ReadStruct(&destM, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pgxscan.Null[int32]{{Value: 1, Valid: true}, {}, {Value: 3, Valid: true}}
+	if !reflect.DeepEqual(destM.IDs, want) {
+		t.Errorf("value mismatch for field IDs: %v", destM.IDs)
+	}
+}
+
+func TestReadStructEmptyArray(t *testing.T) {
+	var dest struct {
+		Names []string
+		IDs   *[]int32
+	}
+
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("names"), DataTypeOID: pgtype.TextArrayOID},
+			{Name: []byte("ids"), DataTypeOID: pgtype.Int4ArrayOID},
+		},
+		vals: []interface{}{mkArray(&pgtype.TextArray{}, []string{}), nil},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Names == nil || len(dest.Names) != 0 {
+		t.Errorf("empty array not assigned as empty slice: %#v", dest.Names)
+	}
+	if dest.IDs != nil {
+		t.Errorf("NULL not assigned as nil: %v", dest.IDs)
+	}
+
+	rows.vals = []interface{}{nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err == nil {
+		t.Error("NULL assigned to slice w/o NilSlices")
+	}
+
+	pgxscan.NilSlices = true
+	defer func() { pgxscan.NilSlices = false }()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Names != nil {
+		t.Errorf("NULL not assigned as nil slice: %#v", dest.Names)
+	}
+}
+
+func TestReadStructFixedArray(t *testing.T) {
+	rows := mkColumnRows("pos", pgtype.Float8ArrayOID, mkArray(&pgtype.Float8Array{}, []float64{1.5, 2, -3}))
+
+	var dest struct {
+		Pos [3]float64
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Pos != [3]float64{1.5, 2, -3} {
+		t.Errorf("value mismatch for field Pos: %v", dest.Pos)
+	}
+
+	var destP struct {
+		Pos *[3]float64
+	}
+	err = pgxscan.ReadStruct(&destP, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destP.Pos == nil || *destP.Pos != [3]float64{1.5, 2, -3} {
+		t.Errorf("value mismatch for field Pos: %v", destP.Pos)
+	}
+
+	var destB struct {
+		Pos [2]float64
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect length mismatch: %v", err)
+	}
+}
+
+func TestReadStructNamedElements(t *testing.T) {
+	type (
+		UserID int64
+		Status string
+		Score  float32
+	)
+
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("ids"), DataTypeOID: pgtype.Int8ArrayOID},
+			{Name: []byte("states"), DataTypeOID: pgtype.TextArrayOID},
+			{Name: []byte("scores"), DataTypeOID: pgtype.Float4ArrayOID},
+		},
+		vals: []interface{}{
+			mkArray(&pgtype.Int8Array{}, []int64{1, 2}),
+			mkArray(&pgtype.TextArray{}, []string{"new", "done"}),
+			mkArray(&pgtype.Float4Array{}, []float32{0.5}),
+		},
+	}
+
+	var dest struct {
+		IDs    []UserID
+		States []Status
+		Scores []Score
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []UserID{1, 2}) {
+		t.Errorf("value mismatch for field IDs: %v", dest.IDs)
+	}
+	if !reflect.DeepEqual(dest.States, []Status{"new", "done"}) {
+		t.Errorf("value mismatch for field States: %v", dest.States)
+	}
+	if !reflect.DeepEqual(dest.Scores, []Score{0.5}) {
+		t.Errorf("value mismatch for field Scores: %v", dest.Scores)
+	}
+}
+
+func TestReadStructWideningElements(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("small"), DataTypeOID: pgtype.Int2ArrayOID},
+			{Name: []byte("medium"), DataTypeOID: pgtype.Int4ArrayOID},
+			{Name: []byte("ratios"), DataTypeOID: pgtype.Float4ArrayOID},
+		},
+		vals: []interface{}{
+			mkArray(&pgtype.Int2Array{}, []int16{-1, 32767}),
+			mkArray(&pgtype.Int4Array{}, []int32{2147483647}),
+			mkArray(&pgtype.Float4Array{}, []float32{0.25, -2}),
+		},
+	}
+
+	var dest struct {
+		Small  []int32
+		Medium []int64
+		Ratios []float64
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Small, []int32{-1, 32767}) {
+		t.Errorf("value mismatch for field Small: %v", dest.Small)
+	}
+	if !reflect.DeepEqual(dest.Medium, []int64{2147483647}) {
+		t.Errorf("value mismatch for field Medium: %v", dest.Medium)
+	}
+	if !reflect.DeepEqual(dest.Ratios, []float64{0.25, -2}) {
+		t.Errorf("value mismatch for field Ratios: %v", dest.Ratios)
+	}
+
+	// no narrowing
+	var destB struct {
+		Medium []int16
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err == nil {
+		t.Error("failed to detect invalid destination type")
+	}
+}
+
+func TestReadStructReuseSlices(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("ids"), DataTypeOID: pgtype.Int8ArrayOID},
+			{Name: []byte("ptrs"), DataTypeOID: pgtype.Int4ArrayOID},
+		},
+		vals: []interface{}{
+			mkArray(&pgtype.Int8Array{}, []int64{1, 2}),
+			mkArray(&pgtype.Int4Array{}, []int32{3}),
+		},
+	}
+
+	ids := make([]int64, 0, 4)
+	ptrs := make([]*int32, 0, 4)
+	dest := struct {
+		IDs  []int64
+		Ptrs []*int32
+	}{IDs: ids, Ptrs: ptrs}
+
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &dest.IDs[0] == &ids[:1][0] {
+		t.Error("slice reused w/o ReuseSlices")
+	}
+
+	pgxscan.ReuseSlices = true
+	defer func() { pgxscan.ReuseSlices = false }()
+	dest.IDs, dest.Ptrs = ids, ptrs
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []int64{1, 2}) || len(dest.Ptrs) != 1 || *dest.Ptrs[0] != 3 {
+		t.Errorf("value mismatch: %v %v", dest.IDs, dest.Ptrs)
+	}
+	if &dest.IDs[0] != &ids[:1][0] || &dest.Ptrs[0] != &ptrs[:1][0] {
+		t.Error("slice not reused")
+	}
+
+	// too small slices are replaced
+	dest.IDs = make([]int64, 0, 1)
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []int64{1, 2}) {
+		t.Errorf("value mismatch for field IDs: %v", dest.IDs)
+	}
+}
+
+func TestReadStructPgtypeArray(t *testing.T) {
+	var arr pgtype.Int8Array
+	err := arr.DecodeText(nil, []byte("[0:1]={1,NULL}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("ids"), DataTypeOID: pgtype.Int8ArrayOID},
+			{Name: []byte("names"), DataTypeOID: pgtype.TextArrayOID},
+		},
+		vals: []interface{}{arr, nil},
+	}
+
+	var dest struct {
+		IDs   pgtype.Int8Array
+		Names *pgtype.TextArray
+	}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, arr) {
+		t.Errorf("value mismatch for field IDs: %+v", dest.IDs)
+	}
+	if dest.IDs.Dimensions[0].LowerBound != 0 || dest.IDs.Elements[1].Status != pgtype.Null {
+		t.Errorf("dimensions or element status lost: %+v", dest.IDs)
+	}
+	if dest.Names != nil {
+		t.Errorf("NULL not assigned as nil: %+v", dest.Names)
+	}
+
+	var destN struct {
+		IDs pgtype.Int8Array
+	}
+	rows.vals = []interface{}{nil, nil}
+	err = pgxscan.ReadStruct(&destN, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destN.IDs.Status != pgtype.Null {
+		t.Errorf("NULL status not kept: %+v", destN.IDs)
+	}
+}
+
+func TestReadStructArrayDims(t *testing.T) {
+	a := pgtype.Int4Array{
+		Elements: []pgtype.Int4{
+			{Int: 1, Status: pgtype.Present}, {Int: 2, Status: pgtype.Present}, {Int: 3, Status: pgtype.Present},
+			{Int: 4, Status: pgtype.Present}, {Int: 5, Status: pgtype.Present}, {Status: pgtype.Null},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 2, LowerBound: 0}, {Length: 3, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := mkColumnRows("grid", pgtype.Int4ArrayOID, a)
+
+	var dest struct {
+		Grid pgxscan.Array[*int32]
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.Grid.Elements) != 6 || *dest.Grid.Elements[0] != 1 || *dest.Grid.Elements[4] != 5 || dest.Grid.Elements[5] != nil {
+		t.Errorf("value mismatch for field Grid.Elements: %v", dest.Grid.Elements)
+	}
+	if !reflect.DeepEqual(dest.Grid.Dims, []int32{2, 3}) || !reflect.DeepEqual(dest.Grid.LowerBounds, []int32{0, 1}) {
+		t.Errorf("dimension mismatch for field Grid: %v %v", dest.Grid.Dims, dest.Grid.LowerBounds)
+	}
+
+	var destE struct {
+		Grid pgxscan.Array[int32]
+	}
+	err = pgxscan.ReadStruct(&destE, mkColumnRows("grid", pgtype.Int4ArrayOID, mkArray(&pgtype.Int4Array{}, []int32{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destE.Grid.Elements) != 0 || len(destE.Grid.Dims) != 0 {
+		t.Errorf("value mismatch for empty array: %+v", destE.Grid)
+	}
+
+	var destP struct {
+		Grid *pgxscan.Array[int32]
+	}
+	err = pgxscan.ReadStruct(&destP, mkColumnRows("grid", pgtype.Int4ArrayOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destP.Grid != nil {
+		t.Errorf("NULL not assigned as nil: %+v", destP.Grid)
+	}
+
+	err = pgxscan.ReadStruct(&destE, mkColumnRows("grid", pgtype.Int4ArrayOID, nil))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect NULL for Array: %v", err)
+	}
+}
+
+func TestReadStructStringKindSlice(t *testing.T) {
+	type Tag string
+
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("tags"), DataTypeOID: pgtype.TextArrayOID},
+			{Name: []byte("labels"), DataTypeOID: pgtype.VarcharArrayOID},
+			{Name: []byte("codes"), DataTypeOID: pgtype.BPCharArrayOID},
+		},
+		vals: []interface{}{
+			mkArray(&pgtype.TextArray{}, []string{"go", "sql"}),
+			mkArray(&pgtype.VarcharArray{}, []string{"a", "b"}),
+			mkArray(&pgtype.BPCharArray{}, []string{"x  ", "yz "}),
+		},
+	}
+
+	var dest struct {
+		Tags   []Tag
+		Labels []Tag
+		Codes  []string
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Tags, []Tag{"go", "sql"}) {
+		t.Errorf("value mismatch for field Tags: %v", dest.Tags)
+	}
+	if !reflect.DeepEqual(dest.Labels, []Tag{"a", "b"}) {
+		t.Errorf("value mismatch for field Labels: %v", dest.Labels)
+	}
+	if !reflect.DeepEqual(dest.Codes, []string{"x  ", "yz "}) {
+		t.Errorf("value mismatch for field Codes: %q", dest.Codes)
+	}
+
+	pgxscan.TrimChar = true
+	defer func() { pgxscan.TrimChar = false }()
+
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Codes, []string{"x", "yz"}) {
+		t.Errorf("char elements not trimmed: %q", dest.Codes)
+	}
+}
+
+func TestReadStructNormalizedElements(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("codes"), DataTypeOID: pgtype.BPCharArrayOID},
+			{Name: []byte("names"), DataTypeOID: pgtype.TextArrayOID},
+		},
+		vals: []interface{}{
+			mkArray(&pgtype.BPCharArray{}, []string{"x  "}),
+			mkArray(&pgtype.TextArray{}, []string{"a", ""}),
+		},
+	}
+
+	var dest struct {
+		Codes pgxscan.Array[string]
+		Names []*string
+	}
+	pgxscan.TrimChar = true
+	pgxscan.EmptyAsNull = true
+	defer func() { pgxscan.TrimChar, pgxscan.EmptyAsNull = false, false }()
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Codes.Elements, []string{"x"}) {
+		t.Errorf("char elements not trimmed: %q", dest.Codes.Elements)
+	}
+	if len(dest.Names) != 2 || *dest.Names[0] != "a" || dest.Names[1] != nil {
+		t.Errorf("empty element not assigned as NULL: %v", dest.Names)
+	}
+}
+
+func TestReadStructJSONElements(t *testing.T) {
+	type doc struct {
+		Colour string `json:"color"`
+	}
+	a := pgtype.JSONBArray{
+		Elements: []pgtype.JSONB{
+			{Bytes: []byte(`{"color":"red"}`), Status: pgtype.Present},
+			{Status: pgtype.Null},
+		},
+		Dimensions: []pgtype.ArrayDimension{{Length: 2, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+
+	var dest struct {
+		Docs    []*doc
+		Array   pgxscan.Array[*doc]
+		Structs []doc
+	}
+	for _, col := range []string{"docs", "array", "structs"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.JSONBArrayOID, a))
+		if err != nil {
+			t.Fatalf("%s: %v", col, err)
+		}
+	}
+	if len(dest.Docs) != 2 || dest.Docs[0] == nil || dest.Docs[0].Colour != "red" || dest.Docs[1] != nil {
+		t.Errorf("value mismatch for field Docs: %+v", dest.Docs)
+	}
+	if len(dest.Array.Elements) != 2 || dest.Array.Elements[0].Colour != "red" {
+		t.Errorf("value mismatch for field Array: %+v", dest.Array)
+	}
+	if !reflect.DeepEqual(dest.Structs, []doc{{Colour: "red"}, {}}) {
+		t.Errorf("value mismatch for field Structs: %+v", dest.Structs)
+	}
+}
@@ -0,0 +1,63 @@
+package pgxscan
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgtype"
+)
+
+// Bitset holds the bits of a bit or varbit column.
+type Bitset struct {
+	// Bytes holds the bits, the first bit is the most significant bit of the first byte.
+	Bytes []byte
+	// Len is the number of bits.
+	Len int
+}
+
+var bitsetType = reflect.TypeOf(Bitset{})
+
+// Bit reports if bit i is set. Bits are counted from 0, like in Postgres' get_bit.
+func (b Bitset) Bit(i int) bool {
+	if i < 0 || i >= b.Len {
+		return false
+	}
+	return b.Bytes[i/8]&(0x80>>(i%8)) != 0
+}
+
+// String returns the bits as string of 0 and 1, like Postgres does.
+func (b Bitset) String() string {
+	var sb strings.Builder
+	sb.Grow(b.Len)
+	for i := 0; i < b.Len; i++ {
+		if b.Bit(i) {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// assignBits assigns the bit string vb to dest, which can be a Bitset, []bool or []byte.
+// A []byte gets the packed bits, unused bits in the last byte are 0.
+func assignBits(dest reflect.Value, vb pgtype.Varbit) error {
+	bs := Bitset{Bytes: append([]byte(nil), vb.Bytes...), Len: int(vb.Len)}
+
+	switch {
+	case dest.Type() == bitsetType:
+		dest.Set(reflect.ValueOf(bs))
+	case dest.Kind() == reflect.Slice && dest.Type().Elem().Kind() == reflect.Bool:
+		bits := reflect.MakeSlice(dest.Type(), bs.Len, bs.Len)
+		for i := 0; i < bs.Len; i++ {
+			bits.Index(i).SetBool(bs.Bit(i))
+		}
+		dest.Set(bits)
+	case isBytes(dest):
+		dest.SetBytes(bs.Bytes)
+	default:
+		return core.Assign(dest, reflect.ValueOf(vb))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package pgxscan_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func mkVarbit(s string) interface{} {
+	var vb pgtype.Varbit
+	err := vb.DecodeText(nil, []byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return vb.Get()
+}
+
+func TestReadStructBits(t *testing.T) {
+	var dest struct {
+		Flags []bool
+		Raw   []byte
+		Set   pgxscan.Bitset
+	}
+	vb := mkVarbit("1010000011")
+	for _, col := range []string{"flags", "raw", "set"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.VarbitOID, vb))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []bool{true, false, true, false, false, false, false, false, true, true}
+	if !reflect.DeepEqual(dest.Flags, want) {
+		t.Errorf("value mismatch for field Flags: %v", dest.Flags)
+	}
+	if !reflect.DeepEqual(dest.Raw, []byte{0xa0, 0xc0}) {
+		t.Errorf("value mismatch for field Raw: %x", dest.Raw)
+	}
+	if dest.Set.Len != 10 || !dest.Set.Bit(8) || dest.Set.Bit(1) || dest.Set.Bit(10) {
+		t.Errorf("value mismatch for field Set: %v", dest.Set)
+	}
+	if dest.Set.String() != "1010000011" {
+		t.Errorf("unexpected string: %s", dest.Set)
+	}
+}
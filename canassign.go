@@ -0,0 +1,217 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// Conversion describes how a DB value is assigned to a Go type.
+type Conversion int
+
+const (
+	// NoConversion means the value can't be assigned.
+	NoConversion Conversion = iota
+	// Exact means the Go type is the type pgx returns for the column.
+	Exact
+	// Converted means the value is converted, which always succeeds.
+	Converted
+	// Checked means the value is converted, but values which don't fit are rejected,
+	// e.g. bigint into int16 or numeric into *big.Int.
+	Checked
+)
+
+func (c Conversion) String() string {
+	switch c {
+	case Exact:
+		return "exact"
+	case Converted:
+		return "converted"
+	case Checked:
+		return "checked"
+	default:
+		return "none"
+	}
+}
+
+// samples are text representations of values of a type.
+// The first one is a typical value, the others are extremes which may not fit every destination.
+var samples = map[uint32][]string{
+	pgtype.BoolOID:        {"t"},
+	pgtype.Int2OID:        {"1", "-32768", "32767"},
+	pgtype.Int4OID:        {"1", "-2147483648", "2147483647"},
+	pgtype.Int8OID:        {"1", "-9223372036854775808", "9223372036854775807"},
+	pgtype.OIDOID:         {"1", "4294967295"},
+	pgtype.Float4OID:      {"1.5"},
+	pgtype.Float8OID:      {"1.5"},
+	pgtype.NumericOID:     {"1", "1.5", "NaN"},
+	pgtype.TextOID:        {"x"},
+	pgtype.VarcharOID:     {"x"},
+	pgtype.BPCharOID:      {"x"},
+	pgtype.NameOID:        {"x"},
+	pgtype.ByteaOID:       {`\x01`},
+	pgtype.JSONOID:        {`{"a":1}`},
+	pgtype.JSONBOID:       {`{"a":1}`},
+	pgtype.UUIDOID:        {"00000000-0000-0000-0000-000000000001"},
+	pgtype.DateOID:        {"2000-01-01"},
+	pgtype.TimeOID:        {"12:00:00"},
+	pgtype.TimestampOID:   {"2000-01-01 00:00:00"},
+	pgtype.TimestamptzOID: {"2000-01-01 00:00:00+00"},
+	pgtype.IntervalOID:    {"1 day", "1 mon"},
+	pgtype.InetOID:        {"127.0.0.1", "10.0.0.0/8"},
+	pgtype.CIDROID:        {"10.0.0.0/8"},
+	pgtype.PointOID:       {"(1,2)"},
+	pgtype.BoxOID:         {"(1,1),(0,0)"},
+	pgtype.CircleOID:      {"<(0,0),1>"},
+	pgtype.LineOID:        {"{1,1,0}"},
+	pgtype.PathOID:        {"[(0,0),(1,1)]"},
+	pgtype.PolygonOID:     {"((0,0),(1,0),(0,1))"},
+	pgtype.VarbitOID:      {"101"},
+	pgtype.BitOID:         {"101"},
+	pgtype.Int4rangeOID:   {"[1,2)"},
+	pgtype.Int8rangeOID:   {"[1,2)"},
+	pgtype.NumrangeOID:    {"[1,2)"},
+	pgtype.DaterangeOID:   {"[2000-01-01,2000-01-02)"},
+	pgtype.TstzrangeOID:   {`["2000-01-01 00:00:00+00","2000-01-02 00:00:00+00")`},
+	pgtype.TextArrayOID:   {"{a,b}"},
+	pgtype.ByteaArrayOID:  {`{"\\x01"}`},
+	pgtype.Int2ArrayOID:   {"{1,2}"},
+	pgtype.Int4ArrayOID:   {"{1,2}"},
+	pgtype.Int8ArrayOID:   {"{1,2}"},
+	pgtype.Float4ArrayOID: {"{1.5}"},
+	pgtype.Float8ArrayOID: {"{1.5}"},
+	moneyOID:              {"$1.00"},
+	xmlOID:                {"<a/>"},
+}
+
+// CanAssignOption sets an option CanAssign answers for.
+// W/o options the answers are for the current settings of NumericFloats and NullElements.
+type CanAssignOption func(*canAssignOptions)
+
+type canAssignOptions struct {
+	numericFloats bool
+	nullElements  NullElementPolicy
+}
+
+// AssignNumericFloats answers like NumericFloats was set to on.
+func AssignNumericFloats(on bool) CanAssignOption {
+	return func(o *canAssignOptions) {
+		o.numericFloats = on
+	}
+}
+
+// AssignNullElements answers like NullElements was set to p.
+func AssignNullElements(p NullElementPolicy) CanAssignOption {
+	return func(o *canAssignOptions) {
+		o.nullElements = p
+	}
+}
+
+// CanAssign reports if values of the Postgres type oid can be assigned to fields of type t and how.
+//
+// The answer is not taken from a table, sample values of the type are decoded like pgx does
+// and assigned w/ the rules ReadStruct uses, so it stays in line w/ the scanner.
+// Registered enums are tried w/ one of their labels, other types w/o samples w/ a text value.
+// Registered converters are not called, they decide which values they accept,
+// so the answer for their types is Checked.
+//
+// NULL elements are taken into account for arrays: w/ NullElementsError arrays assigned to
+// slices of non-nullable elements are Checked.
+func CanAssign(oid uint32, t reflect.Type, opts ...CanAssignOption) (bool, Conversion) {
+	o := canAssignOptions{numericFloats: NumericFloats, nullElements: NullElements}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if hasConverter(t) {
+		return true, Checked
+	}
+	if (oid == pgtype.NumericOID || oid == pgtype.NumericArrayOID) && isFloatKind(baseType(t)) {
+		// rounded to the nearest float, values beyond the range of floats are rejected
+		if !o.numericFloats {
+			return false, NoConversion
+		}
+		return true, Checked
+	}
+
+	texts, ok := samples[oid]
+	if label, isEnum := enumSample(oid); isEnum {
+		texts, ok = []string{label}, true
+	}
+	if !ok {
+		texts = []string{"x"}
+	}
+
+	ci := pgtype.NewConnInfo()
+	fd := pgproto3.FieldDescription{DataTypeOID: oid}
+	conv := NoConversion
+	for i, text := range texts {
+		v, err := decodeRaw(ci, oid, textFormat, []byte(text))
+		if err != nil {
+			continue
+		}
+		dest := reflect.New(t).Elem()
+		err = assignField(dest, v, &fd, "")
+		switch {
+		case i == 0 && err != nil:
+			return false, NoConversion
+		case i == 0 && v != nil && reflect.TypeOf(v) == t:
+			conv = Exact
+		case i == 0:
+			conv = Converted
+		case err != nil:
+			return true, Checked
+		}
+		if i == 0 && o.nullElements == NullElementsError && isArrayValue(v) && !hasNullableElements(t) {
+			// arrays w/ NULL elements are rejected
+			conv = Checked
+		}
+	}
+	return true, conv
+}
+
+// baseType returns the type of the values assigned to t: the element type of slices and
+// Go arrays and the type pointers point to.
+func baseType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// hasConverter checks if a converter is registered for t or its base type.
+func hasConverter(t reflect.Type) bool {
+	return lookupConverter(t) != nil || lookupConverter(baseType(t)) != nil
+}
+
+func isFloatKind(t reflect.Type) bool {
+	return t.Kind() == reflect.Float64 || t.Kind() == reflect.Float32
+}
+
+// isArrayValue checks if v is an array like pgx returns it.
+func isArrayValue(v interface{}) bool {
+	if _, ok := v.([]interface{}); ok {
+		return true
+	}
+	_, _, ok := arrayElements(v)
+	return ok
+}
+
+// hasNullableElements checks if the slice or Go array type t, or the type it points to, has elements which can hold NULL.
+// Other types, like Array[*int32] or pgtype arrays, get NULL elements as they are.
+func hasNullableElements(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return true
+	}
+	return isNullableSlice(reflect.New(reflect.SliceOf(t.Elem())).Elem())
+}
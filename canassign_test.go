@@ -0,0 +1,104 @@
+package pgxscan_test
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestCanAssign(t *testing.T) {
+	type UserID int64
+	for _, tc := range []struct {
+		oid  uint32
+		t    reflect.Type
+		ok   bool
+		conv pgxscan.Conversion
+	}{
+		{pgtype.Int8OID, reflect.TypeOf(int64(0)), true, pgxscan.Exact},
+		{pgtype.Int8OID, reflect.TypeOf(UserID(0)), true, pgxscan.Converted},
+		{pgtype.Int8OID, reflect.TypeOf(int16(0)), false, pgxscan.NoConversion},
+		{pgtype.Int8OID, reflect.TypeOf(uint8(0)), true, pgxscan.Checked},
+		{pgtype.Int4OID, reflect.TypeOf(int(0)), true, pgxscan.Converted},
+		{pgtype.Int8OID, reflect.TypeOf(""), false, pgxscan.NoConversion},
+		{pgtype.NumericOID, reflect.TypeOf(&big.Rat{}), true, pgxscan.Checked},
+		{pgtype.NumericOID, reflect.TypeOf(&big.Int{}), true, pgxscan.Checked},
+		{pgtype.IntervalOID, reflect.TypeOf(time.Duration(0)), true, pgxscan.Checked},
+		{pgtype.IntervalOID, reflect.TypeOf(pgxscan.Interval{}), true, pgxscan.Converted},
+		{pgtype.TextArrayOID, reflect.TypeOf([]string{}), true, pgxscan.Converted},
+		{pgtype.TimestamptzOID, reflect.TypeOf(time.Time{}), true, pgxscan.Exact},
+		{pgtype.TimestamptzOID, reflect.TypeOf(&time.Time{}), true, pgxscan.Converted},
+		{16400, reflect.TypeOf(""), true, pgxscan.Exact},
+	} {
+		ok, conv := pgxscan.CanAssign(tc.oid, tc.t)
+		if ok != tc.ok || conv != tc.conv {
+			t.Errorf("CanAssign(%d, %v) = %v, %v, want %v, %v", tc.oid, tc.t, ok, conv, tc.ok, tc.conv)
+		}
+	}
+}
+
+type canAssignStatus string
+
+func TestCanAssignEnum(t *testing.T) {
+	const statusOID, statusArrayOID = 16394, 16393
+	typ := reflect.TypeOf(canAssignStatus(""))
+
+	pgxscan.RegisterEnum(statusOID, "status", "active", "paused")
+	defer pgxscan.RegisterEnum(statusOID, "status")
+	pgxscan.RegisterEnumArray(statusArrayOID, statusOID)
+
+	if ok, conv := pgxscan.CanAssign(statusOID, typ); !ok || conv != pgxscan.Converted {
+		t.Errorf("CanAssign for enum = %v, %v", ok, conv)
+	}
+	if ok, conv := pgxscan.CanAssign(statusArrayOID, reflect.TypeOf([]string{})); !ok || conv != pgxscan.Converted {
+		t.Errorf("CanAssign for enum array = %v, %v", ok, conv)
+	}
+}
+
+func TestCanAssignConverter(t *testing.T) {
+	typ := reflect.TypeOf(upperString(""))
+	called := false
+	pgxscan.RegisterConverter(typ, func(dest reflect.Value, src interface{}) error {
+		called = true
+		return nil
+	})
+	defer pgxscan.RegisterConverter(typ, nil)
+
+	if ok, conv := pgxscan.CanAssign(pgtype.TextOID, typ); !ok || conv != pgxscan.Checked {
+		t.Errorf("CanAssign for converter = %v, %v", ok, conv)
+	}
+	if ok, conv := pgxscan.CanAssign(pgtype.TextArrayOID, reflect.SliceOf(typ)); !ok || conv != pgxscan.Checked {
+		t.Errorf("CanAssign for slice w/ converter = %v, %v", ok, conv)
+	}
+	if called {
+		t.Error("converter called by CanAssign")
+	}
+}
+
+func TestCanAssignOptions(t *testing.T) {
+	floatType := reflect.TypeOf(float64(0))
+	if ok, _ := pgxscan.CanAssign(pgtype.NumericOID, floatType); ok {
+		t.Error("numeric assignable to float64 w/o NumericFloats")
+	}
+	ok, conv := pgxscan.CanAssign(pgtype.NumericOID, floatType, pgxscan.AssignNumericFloats(true))
+	if !ok || conv != pgxscan.Checked {
+		t.Errorf("CanAssign w/ NumericFloats = %v, %v", ok, conv)
+	}
+
+	// arrays w/ NULL elements are rejected w/ NullElementsError
+	ok, conv = pgxscan.CanAssign(pgtype.Int4ArrayOID, reflect.TypeOf([]int32{}), pgxscan.AssignNullElements(pgxscan.NullElementsError))
+	if !ok || conv != pgxscan.Checked {
+		t.Errorf("CanAssign w/ NullElementsError = %v, %v", ok, conv)
+	}
+	ok, conv = pgxscan.CanAssign(pgtype.Int4ArrayOID, reflect.TypeOf([]*int32{}), pgxscan.AssignNullElements(pgxscan.NullElementsError))
+	if !ok || conv != pgxscan.Converted {
+		t.Errorf("CanAssign for nullable elements = %v, %v", ok, conv)
+	}
+	ok, conv = pgxscan.CanAssign(pgtype.Int4ArrayOID, reflect.TypeOf([]int32{}))
+	if !ok || conv != pgxscan.Converted {
+		t.Errorf("CanAssign w/ NullElementsZero = %v, %v", ok, conv)
+	}
+}
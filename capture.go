@@ -0,0 +1,107 @@
+package pgxscan
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+)
+
+var bytesType = reflect.TypeOf([]byte(nil))
+
+// CapturedRow is a snapshot of a record, which can be read any number of times.
+// It implements PgxRows, so it can be passed to ReadStruct, e.g. once for a full
+// and once for a summary model.
+type CapturedRow struct {
+	fds  []pgproto3.FieldDescription
+	vals []interface{}
+}
+
+// CaptureRow takes a snapshot of the current record in rows.
+//
+// pgx reuses the buffer of a record for the next one, so byte slices, like json.RawMessage,
+// bit strings and the elements of arrays are copied.
+// The snapshot stays valid after rows moved on or are closed.
+func CaptureRow(rows PgxRows) (*CapturedRow, error) {
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	vals, err := rows.Values()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSize(vals, nil); err != nil {
+		return nil, err
+	}
+
+	fds := append([]pgproto3.FieldDescription(nil), rows.FieldDescriptions()...)
+	for i := range fds {
+		fds[i].Name = append([]byte(nil), fds[i].Name...)
+	}
+
+	cr := &CapturedRow{
+		fds:  fds,
+		vals: make([]interface{}, len(vals)),
+	}
+	for i, v := range vals {
+		cr.vals[i] = copyValue(v)
+	}
+	return cr, nil
+}
+
+// copyValue copies the bytes of values that share the read buffer of pgx:
+// byte slices, like json.RawMessage, the Bytes of pgtype values, like bit strings,
+// and the Elements of pgtype arrays.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return copyBytea(v)
+	case json.RawMessage:
+		if v != nil {
+			v = append(json.RawMessage{}, v...)
+		}
+		return v
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		elems := make([]interface{}, len(v))
+		for i, e := range v {
+			elems[i] = copyValue(e)
+		}
+		return elems
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Struct {
+		return v
+	}
+	cp := reflect.New(rv.Type()).Elem()
+	cp.Set(rv)
+	if b := cp.FieldByName("Bytes"); b.IsValid() && b.Type() == bytesType && !b.IsNil() {
+		b.SetBytes(append([]byte{}, b.Bytes()...))
+	}
+	if e := cp.FieldByName("Elements"); e.IsValid() && e.Kind() == reflect.Slice && !e.IsNil() {
+		elems := reflect.MakeSlice(e.Type(), e.Len(), e.Len())
+		for i := 0; i < e.Len(); i++ {
+			elems.Index(i).Set(reflect.ValueOf(copyValue(e.Index(i).Interface())))
+		}
+		e.Set(elems)
+	}
+	return cp.Interface()
+}
+
+// FieldDescriptions returns the descriptions of the captured columns.
+func (cr *CapturedRow) FieldDescriptions() []pgproto3.FieldDescription {
+	return cr.fds
+}
+
+// Values returns the captured values.
+func (cr *CapturedRow) Values() ([]interface{}, error) {
+	return cr.vals, nil
+}
+
+// Err always returns nil, a captured record has no errors.
+func (cr *CapturedRow) Err() error {
+	return nil
+}
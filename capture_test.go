@@ -0,0 +1,139 @@
+package pgxscan_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestCaptureRow(t *testing.T) {
+	rows := mkTestRows()
+	cr, err := pgxscan.CaptureRow(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the buffer is reused for the next record
+	rows.vals[7].([]byte)[0] = 9
+
+	var full struct {
+		Bigid  int64
+		String string
+		X      []byte
+	}
+	err = pgxscan.ReadStruct(&full, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if full.Bigid != 703340046535533321 || full.String != "xy" || !reflect.DeepEqual(full.X, []byte{1, 2, 3}) {
+		t.Errorf("value mismatch: %+v", full)
+	}
+
+	var summary struct {
+		Bigid int64
+	}
+	err = pgxscan.ReadStruct(&summary, cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Bigid != full.Bigid {
+		t.Error("value mismatch for field Bigid")
+	}
+}
+
+func TestCaptureRowDeepCopy(t *testing.T) {
+	buf := []byte{1, 2, 3}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("bs"), DataTypeOID: pgtype.ByteaArrayOID},
+			{Name: []byte("bits"), DataTypeOID: pgtype.VarbitOID},
+		},
+		vals: []interface{}{
+			pgtype.ByteaArray{
+				Elements:   []pgtype.Bytea{{Bytes: buf, Status: pgtype.Present}},
+				Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+				Status:     pgtype.Present,
+			},
+			pgtype.Varbit{Bytes: buf[1:], Len: 16, Status: pgtype.Present},
+		},
+	}
+	cr, err := pgxscan.CaptureRow(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the buffer is reused for the next record
+	buf[0], buf[1] = 9, 9
+
+	vals, _ := cr.Values()
+	if bs := vals[0].(pgtype.ByteaArray); !reflect.DeepEqual(bs.Elements[0].Bytes, []byte{1, 2, 3}) {
+		t.Errorf("captured bytea array changed w/ the buffer: %v", bs.Elements[0].Bytes)
+	}
+	if vb := vals[1].(pgtype.Varbit); !reflect.DeepEqual(vb.Bytes, []byte{2, 3}) {
+		t.Errorf("captured bit string changed w/ the buffer: %v", vb.Bytes)
+	}
+}
+
+// reusingRows decodes its records into the same buffers, like pgx.
+type reusingRows struct {
+	*fakeRows
+	doc   []byte
+	elems []pgtype.Int4
+}
+
+func (r *reusingRows) Next() bool {
+	if !r.fakeRows.Next() {
+		return false
+	}
+	n := int32(r.n)
+	copy(r.doc, fmt.Sprintf(`{"n":%d}`, n))
+	r.elems[0] = pgtype.Int4{Int: n, Status: pgtype.Present}
+	return true
+}
+
+func TestCaptureRowNextRecord(t *testing.T) {
+	doc := make([]byte, 7)
+	elems := make([]pgtype.Int4, 1)
+	rows := &reusingRows{
+		fakeRows: &fakeRows{testRows: testRows{
+			fds: []pgproto3.FieldDescription{
+				{Name: []byte("doc"), DataTypeOID: pgtype.JSONBOID},
+				{Name: []byte("ids"), DataTypeOID: pgtype.Int4ArrayOID},
+			},
+			vals: []interface{}{
+				json.RawMessage(doc),
+				pgtype.Int4Array{
+					Elements:   elems,
+					Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+					Status:     pgtype.Present,
+				},
+			},
+		}, n: 2},
+		doc:   doc,
+		elems: elems,
+	}
+
+	if !rows.Next() {
+		t.Fatal("no records")
+	}
+	cr, err := pgxscan.CaptureRow(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rows.Next() {
+		t.Fatal("second record missing")
+	}
+
+	vals, _ := cr.Values()
+	if doc := vals[0].(json.RawMessage); string(doc) != `{"n":1}` {
+		t.Errorf("captured json changed w/ the next record: %s", doc)
+	}
+	if ids := vals[1].(pgtype.Int4Array); ids.Elements[0].Int != 1 {
+		t.Errorf("captured array changed w/ the next record: %v", ids.Elements)
+	}
+}
@@ -0,0 +1,173 @@
+package pgxscan
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrCheckFailed is returned by CheckAll if at least one registered check failed.
+var ErrCheckFailed = errors.New("mapping check failed")
+
+// Mapping describes how ReadStruct matches the columns of a result to the fields of a struct.
+type Mapping struct {
+	// Fields holds the name of the matching struct field for every column.
+	// The name is empty if the column has no matching field.
+	Fields []string
+	// UnmatchedColumns lists the columns w/o a matching field.
+	UnmatchedColumns []string
+	// UnmatchedFields lists the struct fields no column is assigned to.
+	UnmatchedFields []string
+}
+
+// ExplainMapping reports how ReadStruct would match the columns described by fds to the fields of dest.
+//
+// dest has to be a pointer to a struct, like for ReadStruct.
+func ExplainMapping(dest interface{}, fds []pgproto3.FieldDescription) (Mapping, error) {
+	structData, err := core.StructOf(dest)
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	structFields := core.Fields(structData.Type())
+	allFields := append([]string(nil), structFields...)
+
+	m := Mapping{
+		Fields: matchColumns(structFields, fds, structMatcher(structData.Type()), columnMapping(structData.Type())),
+	}
+
+	matched := make(map[string]bool, len(m.Fields))
+	for i, f := range m.Fields {
+		if len(f) < 1 {
+			m.UnmatchedColumns = append(m.UnmatchedColumns, string(fds[i].Name))
+			continue
+		}
+		matched[f] = true
+	}
+	// embedded structs can repeat a name, only report it once
+	for _, f := range allFields {
+		if !matched[f] {
+			m.UnmatchedFields = append(m.UnmatchedFields, f)
+			matched[f] = true
+		}
+	}
+
+	return m, nil
+}
+
+// Preparer is the interface used by CheckAll to describe queries.
+// It is implemented by pgx.Conn and pgx.Tx.
+type Preparer interface {
+	Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error)
+}
+
+// TxBeginner is the interface used by CheckAllPool to get a connection.
+// It is implemented by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// *pgxpool.Pool has the same Begin method
+var (
+	_ TxBeginner = (*pgx.Conn)(nil)
+	_ TxBeginner = pgx.Tx(nil)
+)
+
+// CheckResult is the outcome of a check registered with RegisterCheck.
+type CheckResult struct {
+	SQL     string
+	Mapping Mapping
+	// Err is set if the query could not be described or dest is not a valid destination.
+	Err error
+}
+
+// Failed reports if the query is broken or leaves struct fields unassigned.
+func (r CheckResult) Failed() bool {
+	return r.Err != nil || len(r.Mapping.UnmatchedFields) > 0
+}
+
+type check struct {
+	sql  string
+	dest interface{}
+}
+
+var (
+	checksMu sync.Mutex
+	checks   []check
+)
+
+// RegisterCheck registers a query whose result is read into dest for validation by CheckAll.
+//
+// dest is only used for its type, a pointer to the zero value is fine.
+func RegisterCheck(sql string, dest interface{}) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+
+	checks = append(checks, check{sql: sql, dest: dest})
+}
+
+// CheckAll describes every query registered with RegisterCheck and matches its result
+// columns against the registered destination.
+//
+// A check fails if the query can not be described, e.g. because a column or table
+// does not exist anymore, or if a struct field is not matched by any column.
+// Extra columns are fine, ReadStruct ignores them.
+//
+// The results of all checks are returned. If any check failed, ErrCheckFailed is returned as well.
+// Queries are described using the unnamed prepared statement, nothing is executed.
+// It is described in a single round trip, so this works behind poolers in transaction mode, like PgBouncer.
+func CheckAll(ctx context.Context, p Preparer) ([]CheckResult, error) {
+	return runChecks(func(sql string) (*pgconn.StatementDescription, error) {
+		return p.Prepare(ctx, "", sql)
+	})
+}
+
+// CheckAllPool runs the checks like CheckAll, but w/ a pool, which can't describe queries itself.
+//
+//	results, err := pgxscan.CheckAllPool(ctx, pool)
+//
+// Every query is described in a transaction of its own, which is rolled back right away.
+// So the connection goes back to the pool after every check and a failed check,
+// which aborts its transaction, doesn't affect the others.
+func CheckAllPool(ctx context.Context, b TxBeginner) ([]CheckResult, error) {
+	return runChecks(func(sql string) (*pgconn.StatementDescription, error) {
+		tx, err := b.Begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback(ctx)
+		return tx.Prepare(ctx, "", sql)
+	})
+}
+
+// runChecks runs all registered checks, describe returns the description of a query.
+func runChecks(describe func(sql string) (*pgconn.StatementDescription, error)) ([]CheckResult, error) {
+	checksMu.Lock()
+	todo := append([]check(nil), checks...)
+	checksMu.Unlock()
+
+	var err error
+	results := make([]CheckResult, 0, len(todo))
+	for _, c := range todo {
+		res := CheckResult{SQL: c.sql}
+
+		sd, perr := describe(c.sql)
+		if perr != nil {
+			res.Err = perr
+		} else {
+			res.Mapping, res.Err = ExplainMapping(c.dest, sd.Fields)
+		}
+
+		if res.Failed() {
+			err = ErrCheckFailed
+		}
+		results = append(results, res)
+	}
+
+	return results, err
+}
@@ -0,0 +1,154 @@
+package pgxscan_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+)
+
+func TestExplainMapping(t *testing.T) {
+	rows := mkTestRows()
+
+	type base struct {
+		Bigid int64
+		Gone  string
+	}
+	var dest struct {
+		base
+		String string
+		N      float32
+		Gone   string
+	}
+
+	m, err := pgxscan.ExplainMapping(&dest, rows.FieldDescriptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.Fields[:5], []string{"Bigid", "", "", "String", "N"}) {
+		t.Errorf("field mismatch: %v", m.Fields)
+	}
+	if len(m.UnmatchedColumns) != len(rows.FieldDescriptions())-3 {
+		t.Errorf("unmatched columns mismatch: %v", m.UnmatchedColumns)
+	}
+	if !reflect.DeepEqual(m.UnmatchedFields, []string{"Gone"}) {
+		t.Errorf("unmatched fields mismatch: %v", m.UnmatchedFields)
+	}
+
+	_, err = pgxscan.ExplainMapping(dest, rows.FieldDescriptions())
+	if err != pgxscan.ErrNotPointer {
+		t.Errorf("non-pointer not detected, error: %v", err)
+	}
+}
+
+type testPreparer map[string][]pgproto3.FieldDescription
+
+func (p testPreparer) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	fds, ok := p[sql]
+	if !ok {
+		return nil, errors.New("no such query")
+	}
+	return &pgconn.StatementDescription{SQL: sql, Fields: fds}, nil
+}
+
+func TestCheckAll(t *testing.T) {
+	type rec struct {
+		Bigid  int64
+		String string
+	}
+
+	p := testPreparer{
+		"ok":      mkTestRows().FieldDescriptions(),
+		"dropped": mkTestRows().FieldDescriptions()[:1],
+	}
+	pgxscan.RegisterCheck("ok", &rec{})
+
+	res, err := pgxscan.CheckAll(context.Background(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Failed() {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	pgxscan.RegisterCheck("dropped", &rec{})
+	pgxscan.RegisterCheck("missing", &rec{})
+
+	res, err = pgxscan.CheckAll(context.Background(), p)
+	if err != pgxscan.ErrCheckFailed {
+		t.Fatalf("failed checks not reported, error: %v", err)
+	}
+	if len(res) != 3 {
+		t.Fatalf("unexpected number of results: %d", len(res))
+	}
+	if !reflect.DeepEqual(res[1].Mapping.UnmatchedFields, []string{"String"}) {
+		t.Errorf("missing column not reported: %+v", res[1])
+	}
+	if res[2].Err == nil {
+		t.Errorf("prepare error not reported: %+v", res[2])
+	}
+}
+
+// testPool has the Begin method of *pgxpool.Pool.
+type testPool struct {
+	p          testPreparer
+	begun      int
+	rolledBack int
+}
+
+func (p *testPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	p.begun++
+	return &testTx{pool: p}, nil
+}
+
+type testTx struct {
+	pgx.Tx // only Prepare and Rollback are used
+	pool   *testPool
+}
+
+func (tx *testTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	return tx.pool.p.Prepare(ctx, name, sql)
+}
+
+func (tx *testTx) Rollback(ctx context.Context) error {
+	tx.pool.rolledBack++
+	return nil
+}
+
+func TestCheckAllPool(t *testing.T) {
+	type rec struct {
+		Bigid  int64
+		String string
+	}
+	pgxscan.RegisterCheck("pool ok", &rec{})
+	pgxscan.RegisterCheck("pool dropped", &rec{})
+
+	pool := &testPool{p: testPreparer{
+		"pool ok":      mkTestRows().FieldDescriptions(),
+		"pool dropped": mkTestRows().FieldDescriptions()[:1],
+	}}
+	res, err := pgxscan.CheckAllPool(context.Background(), pool)
+	if err != pgxscan.ErrCheckFailed {
+		t.Fatalf("failed check not reported, error: %v", err)
+	}
+	for _, r := range res {
+		switch r.SQL {
+		case "pool ok":
+			if r.Failed() {
+				t.Errorf("unexpected result: %+v", r)
+			}
+		case "pool dropped":
+			if !reflect.DeepEqual(r.Mapping.UnmatchedFields, []string{"String"}) {
+				t.Errorf("missing column not reported: %+v", r)
+			}
+		}
+	}
+	if pool.begun != len(res) || pool.rolledBack != pool.begun {
+		t.Errorf("transactions not rolled back: %d begun, %d rolled back", pool.begun, pool.rolledBack)
+	}
+}
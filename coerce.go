@@ -0,0 +1,102 @@
+package pgxscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgtype"
+)
+
+// parseNumber parses the text s into the numeric field dest.
+func parseNumber(dest reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	bits := dest.Type().Bits()
+
+	var err error
+	switch dest.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(s, 10, bits)
+		if err == nil {
+			dest.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(s, 10, bits)
+		if err == nil {
+			dest.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, bits)
+		if err == nil {
+			dest.SetFloat(f)
+		}
+	default:
+		return ErrInvalidDestination
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("%w: %q does not fit into %s", ErrOutOfRange, s, dest.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a number", ErrInvalidDestination, s)
+	}
+	return nil
+}
+
+// isNumber checks for integer and floating point kinds.
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatNumber returns the text of the numeric value v.
+// false is returned if v is not a number.
+func formatNumber(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case pgtype.Numeric:
+		return numericString(v), true
+	}
+	return "", false
+}
+
+// coerceBool returns the bool for integers and text like Postgres casts them:
+// integers are true unless 0, text can be t, true, y, yes, on, 1 and their negations.
+// false is returned as second value if v can't be coerced.
+func coerceBool(v interface{}) (bool, bool) {
+	switch v := v.(type) {
+	case int16:
+		return v != 0, true
+	case int32:
+		return v != 0, true
+	case int64:
+		return v != 0, true
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "t", "true", "y", "yes", "on", "1":
+			return true, true
+		case "f", "false", "n", "no", "off", "0":
+			return false, true
+		}
+	}
+	return false, false
+}
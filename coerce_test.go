@@ -0,0 +1,136 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructParseText(t *testing.T) {
+	var dest struct {
+		I int64
+		U uint16
+		F float32
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("i", pgtype.TextOID, "42"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("text parsed w/o ParseText, error: %v", err)
+	}
+
+	pgxscan.ParseText = true
+	defer func() { pgxscan.ParseText = false }()
+	for col, v := range map[string]string{"i": "-42", "u": " 65535 ", "f": "1.5"} {
+		err = pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.VarcharOID, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.I != -42 || dest.U != 65535 || dest.F != 1.5 {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("u", pgtype.TextOID, "65536"))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("i", pgtype.TextOID, "4x"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid number not detected, error: %v", err)
+	}
+}
+
+func TestReadStructFormatNumbers(t *testing.T) {
+	type ID string
+	var dest struct {
+		Bigid  ID
+		N      string
+		Num    string
+		String string
+	}
+	var num pgtype.Numeric
+	if err := num.Set("12.50"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("number formatted w/o FormatNumbers, error: %v", err)
+	}
+
+	pgxscan.FormatNumbers = true
+	defer func() { pgxscan.FormatNumbers = false }()
+	err = pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("num", pgtype.NumericOID, num))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != "703340046535533321" || dest.N != "42.1" || dest.Num != "12.50" || dest.String != "xy" {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+}
+
+func TestFormatNumeric(t *testing.T) {
+	pgxscan.FormatNumbers = true
+	defer func() { pgxscan.FormatNumbers = false }()
+
+	for _, s := range []string{"0", "-0.05", "1234500", "3.14159", "NaN"} {
+		var num pgtype.Numeric
+		if err := num.DecodeText(nil, []byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		var dest string
+		err := pgxscan.Read(&dest, mkColumnRows("num", pgtype.NumericOID, num))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest != s {
+			t.Errorf("unexpected text: %q, want %q", dest, s)
+		}
+	}
+}
+
+func TestCoerceBool(t *testing.T) {
+	var dest bool
+	err := pgxscan.Read(&dest, mkColumnRows("b", pgtype.Int2OID, int16(1)))
+	if err == nil {
+		t.Error("smallint assigned to bool w/o CoerceBool")
+	}
+
+	pgxscan.CoerceBool = true
+	defer func() { pgxscan.CoerceBool = false }()
+
+	tests := []struct {
+		oid  uint32
+		v    interface{}
+		want bool
+	}{
+		{pgtype.Int2OID, int16(1), true},
+		{pgtype.Int2OID, int16(0), false},
+		{pgtype.Int4OID, int32(-1), true},
+		{pgtype.TextOID, "t", true},
+		{pgtype.TextOID, "f", false},
+		{pgtype.TextOID, "TRUE", true},
+		{pgtype.BPCharOID, "false", false},
+		{pgtype.BoolOID, true, true},
+	}
+	for _, tt := range tests {
+		dest = !tt.want
+		err = pgxscan.Read(&dest, mkColumnRows("b", tt.oid, tt.v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest != tt.want {
+			t.Errorf("value mismatch for %v: %t", tt.v, dest)
+		}
+	}
+
+	err = pgxscan.Read(&dest, mkColumnRows("b", pgtype.TextOID, "maybe"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect invalid bool text: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+var (
+	compositesMu sync.RWMutex
+	// composites maps the OIDs of composite types to the OIDs of their attributes, keyed by name
+	composites = map[uint32]map[string]uint32{}
+	// compositeArrays maps the OIDs of arrays of composite types to the OIDs of their composite type
+	compositeArrays = map[uint32]uint32{}
+)
+
+// RegisterComposite registers the attribute types of the composite type w/ the given OID.
+// ct is the type registered w/ the ConnInfo of the connection, see pgtype.NewCompositeType.
+//
+// pgx returns composite values w/o the types of their attributes.
+// The attributes of registered types are assigned like columns of their types,
+// e.g. json attributes are unmarshaled into struct and map fields.
+// A nil ct removes the registration.
+func RegisterComposite(oid uint32, ct *pgtype.CompositeType) {
+	compositesMu.Lock()
+	defer compositesMu.Unlock()
+
+	if ct == nil {
+		delete(composites, oid)
+		return
+	}
+	attrs := map[string]uint32{}
+	for _, f := range ct.Fields() {
+		attrs[f.Name] = f.OID
+	}
+	composites[oid] = attrs
+}
+
+// RegisterCompositeArray registers arrayOID as array of the composite type oid,
+// so the attributes of its elements are assigned like those of the registered composite type.
+func RegisterCompositeArray(arrayOID, oid uint32) {
+	compositesMu.Lock()
+	defer compositesMu.Unlock()
+
+	compositeArrays[arrayOID] = oid
+}
+
+// attributeOIDs returns the OIDs of the attributes of the composite type oid, nil if it isn't registered.
+func attributeOIDs(oid uint32) map[string]uint32 {
+	compositesMu.RLock()
+	defer compositesMu.RUnlock()
+
+	return composites[oid]
+}
+
+// compositeElementOID returns the composite type of the elements of the array type oid, 0 if it isn't registered.
+func compositeElementOID(oid uint32) uint32 {
+	compositesMu.RLock()
+	defer compositesMu.RUnlock()
+
+	return compositeArrays[oid]
+}
+
+// assignComposite assigns a composite value to the struct dest.
+// pgx returns composite values of types registered w/ pgtype.NewCompositeType as map of attribute names to values.
+// The attributes are matched w/ the fields like columns.
+// fd describes the composite type, the types of the attributes are known if it is registered w/ RegisterComposite.
+func assignComposite(dest reflect.Value, attrs map[string]interface{}, fd *pgproto3.FieldDescription) error {
+	if dest.Kind() != reflect.Struct {
+		return ErrInvalidDestination
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names) // match in a stable order
+
+	oids := attributeOIDs(fd.DataTypeOID)
+	fds := make([]pgproto3.FieldDescription, len(names))
+	for i, name := range names {
+		fds[i].Name = []byte(name)
+		fds[i].DataTypeOID = oids[name]
+	}
+
+	fieldNames := matchColumns(core.Fields(dest.Type()), fds, structMatcher(dest.Type()), columnMapping(dest.Type()))
+
+	for i, fieldName := range fieldNames {
+		if fieldName == "" {
+			continue
+		}
+		f := dest.FieldByName(fieldName)
+		if !f.CanSet() {
+			continue
+		}
+		err := assignValue(f, attrs[names[i]], &fds[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignCompositeSlice assigns the elements of an array of composite values to the slice dest.
+// pgx returns arrays of types registered w/ pgtype.NewArrayType as []interface{}.
+// NULL elements are handled according to NullElements, unless the elements can hold NULL.
+func assignCompositeSlice(dest reflect.Value, elems []interface{}, fd *pgproto3.FieldDescription) error {
+	if dest.Kind() != reflect.Slice {
+		return ErrInvalidDestination
+	}
+
+	efd := elementFD(fd)
+	nullable := isNullableSlice(dest) || dest.Type().Elem().Kind() == reflect.Interface
+	res := makeSlice(dest, len(elems))
+	for i, e := range elems {
+		if e == nil && !nullable {
+			if NullElements == NullElementsError {
+				return fmt.Errorf("%w: NULL element at index %d", ErrInvalidDestination, i)
+			}
+			// the element is zero already
+			continue
+		}
+		err := assignValue(res.Index(i), e, efd)
+		if err != nil {
+			return err
+		}
+	}
+	dest.Set(res)
+	return nil
+}
@@ -0,0 +1,156 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructComposite(t *testing.T) {
+	type item struct {
+		Name  string
+		Qty   int32
+		Price *float64
+	}
+
+	price := 2.5
+	// pgx returns arrays of registered composite types like this
+	items := []interface{}{
+		map[string]interface{}{"name": "apple", "qty": int32(3), "price": 2.5},
+		map[string]interface{}{"name": "pear", "qty": int32(1), "price": nil},
+	}
+
+	var dest struct {
+		Items []item
+		First item
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("items", 0, items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []item{{Name: "apple", Qty: 3, Price: &price}, {Name: "pear", Qty: 1}}
+	if !reflect.DeepEqual(dest.Items, want) {
+		t.Errorf("value mismatch for field Items: %+v", dest.Items)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("first", 0, items[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.First, want[0]) {
+		t.Errorf("value mismatch for field First: %+v", dest.First)
+	}
+
+	// type mismatch of an attribute
+	var destB struct {
+		Items []struct{ Qty string }
+	}
+	err = pgxscan.ReadStruct(&destB, mkColumnRows("items", 0, items))
+	if err == nil {
+		t.Error("failed to detect invalid attribute type")
+	}
+}
+
+func TestReadStructCompositeSliceNull(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	items := []interface{}{map[string]interface{}{"name": "apple"}, nil}
+
+	var dest struct {
+		Items    []item
+		Pointers []*item
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("items", 0, items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Items, []item{{Name: "apple"}, {}}) {
+		t.Errorf("value mismatch for field Items: %+v", dest.Items)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("pointers", 0, items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.Pointers) != 2 || dest.Pointers[0].Name != "apple" || dest.Pointers[1] != nil {
+		t.Errorf("value mismatch for field Pointers: %+v", dest.Pointers)
+	}
+
+	pgxscan.NullElements = pgxscan.NullElementsError
+	defer func() { pgxscan.NullElements = pgxscan.NullElementsZero }()
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("items", 0, items))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NULL element not rejected, error: %v", err)
+	}
+}
+
+func TestReadStructCompositeJSON(t *testing.T) {
+	const itemOID, itemArrayOID = 16500, 16501
+	ct, err := pgtype.NewCompositeType("item", []pgtype.CompositeTypeField{
+		{Name: "name", OID: pgtype.TextOID},
+		{Name: "meta", OID: pgtype.JSONBOID},
+	}, pgtype.NewConnInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pgxscan.RegisterComposite(itemOID, ct)
+	defer pgxscan.RegisterComposite(itemOID, nil)
+	pgxscan.RegisterCompositeArray(itemArrayOID, itemOID)
+
+	type meta struct {
+		Colour string `json:"color"`
+	}
+	type item struct {
+		Name string
+		Meta meta
+	}
+	// pgx returns jsonb attributes decoded, like jsonb columns
+	v := map[string]interface{}{"name": "apple", "meta": map[string]interface{}{"color": "red"}}
+
+	var dest struct {
+		Item  item
+		Items []item
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("item", itemOID, v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Item.Name != "apple" || dest.Item.Meta.Colour != "red" {
+		t.Errorf("value mismatch for field Item: %+v", dest.Item)
+	}
+
+	var destM struct {
+		Item struct {
+			Meta map[string]interface{}
+		}
+	}
+	err = pgxscan.ReadStruct(&destM, mkColumnRows("item", itemOID, v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destM.Item.Meta["color"] != "red" {
+		t.Errorf("value mismatch for field Meta: %v", destM.Item.Meta)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("items", itemArrayOID, []interface{}{v}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.Items) != 1 || dest.Items[0].Meta.Colour != "red" {
+		t.Errorf("value mismatch for field Items: %+v", dest.Items)
+	}
+
+	// w/o registration the attribute types are unknown, json tags are ignored
+	pgxscan.RegisterComposite(itemOID, nil)
+	dest.Item = item{}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("item", itemOID, v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Item.Meta.Colour != "" {
+		t.Errorf("json attribute of unregistered type unmarshaled: %+v", dest.Item)
+	}
+}
@@ -0,0 +1,65 @@
+package pgxscan
+
+import (
+	"context"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// extensionTypes are the types PrepareConn registers if they exist in the database.
+// Their OIDs are assigned when the extension is created, so pgx can't know them in advance.
+var extensionTypes = map[string]func() pgtype.Value{
+	"hstore":  func() pgtype.Value { return &pgtype.Hstore{} },
+	"_hstore": func() pgtype.Value { return &pgtype.HstoreArray{} },
+}
+
+// PrepareConn registers the data types pgxscan relies on with the connection.
+//
+// Types of extensions like hstore get their OID when the extension is created,
+// so pgx does not know them after connect and returns their values undecoded.
+// PrepareConn looks up the types in the database and registers them.
+// Types of extensions which are not installed are skipped.
+//
+// The names of types w/ a decoder registered by RegisterTypeDecoder are resolved as well.
+//
+// The signature matches AfterConnect of pgxpool.Config, so it can be used directly.
+// No statement is prepared, so it works behind poolers in transaction mode as well.
+func PrepareConn(ctx context.Context, conn *pgx.Conn) error {
+	names := make([]string, 0, len(extensionTypes))
+	for name := range extensionTypes {
+		names = append(names, name)
+	}
+	names = append(names, typeDecoderNames()...)
+
+	// w/ the simple protocol, so no statement is prepared, see WithPoolerCompatibility
+	var typeNames pgtype.TextArray
+	err := typeNames.Set(names)
+	if err != nil {
+		return err
+	}
+	rows, err := conn.Query(ctx, "SELECT oid, typname FROM pg_type WHERE typname = ANY($1)",
+		pgx.QuerySimpleProtocol(true), &typeNames)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	ci := conn.ConnInfo()
+	for rows.Next() {
+		var (
+			oid  uint32
+			name string
+		)
+		err = rows.Scan(&oid, &name)
+		if err != nil {
+			return err
+		}
+		if newValue, ok := extensionTypes[name]; ok {
+			ci.RegisterDataType(pgtype.DataType{Value: newValue(), Name: name, OID: oid})
+		}
+		resolveTypeDecoder(name, oid)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,45 @@
+package pgxscan
+
+import (
+	"reflect"
+	"sync"
+)
+
+// ConverterFnc is the signature for a function assigning a DB value to a destination field.
+// dest is the settable struct field and src the value returned from the query, as produced by pgx.
+// src is nil for NULL values.
+type ConverterFnc func(dest reflect.Value, src interface{}) error
+
+var (
+	convertersMu sync.RWMutex
+	converters   = map[reflect.Type]ConverterFnc{}
+)
+
+// RegisterConverter registers fnc for all destination fields of type t.
+//
+// Registered converters take precedence over the built-in assignment rules.
+// This allows packages adding support for further types to live outside of pgxscan
+// and register themselves in their init function, so that importing them is enough:
+//
+//	import _ "example.com/pgxscan-postgis"
+//
+// Registering a converter for a type again replaces the previous one.
+// A nil fnc removes the converter for t.
+func RegisterConverter(t reflect.Type, fnc ConverterFnc) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+
+	if fnc == nil {
+		delete(converters, t)
+		return
+	}
+	converters[t] = fnc
+}
+
+// lookupConverter returns the converter registered for t or nil.
+func lookupConverter(t reflect.Type) ConverterFnc {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+
+	return converters[t]
+}
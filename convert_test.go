@@ -0,0 +1,91 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+type upperString string
+
+func TestRegisterConverter(t *testing.T) {
+	typ := reflect.TypeOf(upperString(""))
+	pgxscan.RegisterConverter(typ, func(dest reflect.Value, src interface{}) error {
+		s, ok := src.(string)
+		if !ok {
+			return pgxscan.ErrInvalidDestination
+		}
+		dest.SetString(strings.ToUpper(s))
+		return nil
+	})
+	defer pgxscan.RegisterConverter(typ, nil)
+
+	var dest struct {
+		Name upperString
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("name", pgtype.TextOID, "abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Name != "ABC" {
+		t.Errorf("value mismatch for field Name: %s", dest.Name)
+	}
+
+	// errors from converters are passed on
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("name", pgtype.Int8OID, int64(1)))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("converter error not returned, error: %v", err)
+	}
+}
+
+func TestConverterPanic(t *testing.T) {
+	typ := reflect.TypeOf(upperString(""))
+	pgxscan.RegisterConverter(typ, func(dest reflect.Value, src interface{}) error {
+		dest.SetInt(1) // wrong kind, panics
+		return nil
+	})
+	defer pgxscan.RegisterConverter(typ, nil)
+
+	var dest struct {
+		Name upperString
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("name", pgtype.TextOID, "abc"))
+	var serr *pgxscan.ScanError
+	if !errors.As(err, &serr) {
+		t.Fatalf("panic not returned as ScanError, error: %v", err)
+	}
+	if serr.Field != "Name" || serr.Column != "name" || serr.Panic == nil || len(serr.Stack) == 0 {
+		t.Errorf("incomplete ScanError: %+v", serr)
+	}
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("ScanError does not wrap ErrInvalidDestination: %v", err)
+	}
+}
+
+// panicScanner panics when scanning.
+type panicScanner struct{}
+
+func (*panicScanner) Scan(src interface{}) error {
+	panic("boom")
+}
+
+func TestScannerPanic(t *testing.T) {
+	var dest struct {
+		Name panicScanner
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("name", pgtype.TextOID, "abc"))
+	var serr *pgxscan.ScanError
+	if !errors.As(err, &serr) {
+		t.Fatalf("panic not returned as ScanError, error: %v", err)
+	}
+	if serr.Field != "Name" || serr.Panic != "boom" || len(serr.Stack) == 0 {
+		t.Errorf("incomplete ScanError: %+v", serr)
+	}
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("ScanError does not wrap ErrInvalidDestination: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package core
+
+import "reflect"
+
+// Assign sets dest to src if the types allow it: src has to be assignable to dest
+// or a named type w/ the same kind, e.g. type UserID int64 for an int64.
+// Otherwise ErrInvalidDestination is returned.
+func Assign(dest, src reflect.Value) error {
+	if !src.IsValid() {
+		return ErrInvalidDestination
+	}
+	if src.Type().AssignableTo(dest.Type()) {
+		dest.Set(src)
+		return nil
+	}
+	// named types w/ the same underlying type, e.g. type UserID int64
+	if src.Kind() == dest.Kind() && src.Type().ConvertibleTo(dest.Type()) {
+		dest.Set(src.Convert(dest.Type()))
+		return nil
+	}
+	return ErrInvalidDestination
+}
@@ -0,0 +1,38 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+func TestAssign(t *testing.T) {
+	type UserID int64
+
+	var id UserID
+	err := core.Assign(reflect.ValueOf(&id).Elem(), reflect.ValueOf(int64(7)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 7 {
+		t.Errorf("value mismatch: %v", id)
+	}
+
+	var i interface{}
+	err = core.Assign(reflect.ValueOf(&i).Elem(), reflect.ValueOf("x"))
+	if err != nil || i != "x" {
+		t.Errorf("value mismatch: %v, error: %v", i, err)
+	}
+
+	// no conversions between kinds
+	var s string
+	err = core.Assign(reflect.ValueOf(&s).Elem(), reflect.ValueOf(int64(7)))
+	if err != core.ErrInvalidDestination {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err = core.Assign(reflect.ValueOf(&s).Elem(), reflect.Value{})
+	if err != core.ErrInvalidDestination {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Package core is the engine of pgxscan matching result columns to struct fields
+// and assigning plain Go values.
+//
+// It depends on the standard library only, so it can be used w/ other drivers or wire
+// formats, like in tests, w/o pulling in pgx. The package pgxscan binds it to pgx v4,
+// there the values are decoded and the Postgres types are assigned.
+package core
+
+import "errors"
+
+var (
+	// ErrNotPointer is returned when the destination is not a pointer.
+	ErrNotPointer = errors.New("arg not a pointer")
+	// ErrNotStruct is returned when the dereferenced destination pointer does not point to a struct.
+	ErrNotStruct = errors.New("arg not a struct")
+	// ErrDestNil is returned when the destination is nil or points to nothing.
+	ErrDestNil = errors.New("destination is nil")
+	// ErrEmptyStruct is returned if the destination struct has no fields.
+	ErrEmptyStruct = errors.New("destination struct has no fields")
+	// ErrInvalidDestination is returned when the destination does not match the type of the value.
+	ErrInvalidDestination = errors.New("destination has incompatible type")
+)
+
+// Column describes a column of a result.
+type Column struct {
+	// Name is the name of the column.
+	Name string
+	// TypeOID is the OID of the Postgres type of the column.
+	TypeOID uint32
+	// Format is the format of the values, 0 for text and 1 for binary.
+	Format int16
+}
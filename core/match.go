@@ -0,0 +1,105 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MatcherFnc reports if the struct field fieldName matches the column columnName.
+type MatcherFnc func(fieldName, columnName string) bool
+
+// MatchFold matches names which are equal under Unicode case folding, like ReadStruct does by default.
+// Empty names never match.
+func MatchFold(fieldName, columnName string) bool {
+	if len(fieldName) < 1 || len(columnName) < 1 {
+		return false
+	}
+	return strings.EqualFold(fieldName, columnName)
+}
+
+// Fields returns the names of the exported fields of the struct type t.
+// The fields of embedded structs are included, the embedded structs are not.
+func Fields(t reflect.Type) []string {
+	fields := make([]string, 0, 20) // preallocate, enough for most structs
+	collectFields(t, &fields)
+	return fields
+}
+
+// helper to recursively collect all field names from the given struct
+func collectFields(t reflect.Type, m *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous && !field.IsExported() {
+			continue
+		}
+		// only embedded structs contribute their fields
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectFields(field.Type, m)
+			continue
+		}
+		*m = append(*m, field.Name)
+	}
+}
+
+// Match returns the name of the matching field for every column in cols.
+// Columns w/o a matching field get an empty name.
+// Every field is used only once, fields holds the candidates and is reordered.
+// Fields in mapping only match the column name they are mapped to, all others are matched by match.
+func Match(fields []string, cols []Column, match MatcherFnc, mapping map[string]string) []string {
+	fieldNames := make([]string, len(cols))
+
+	for i := 0; i < len(cols) && len(fields) > 0; i++ {
+		for j, k := range fields {
+			var ok bool
+			if col, mapped := mapping[k]; mapped {
+				ok = col == cols[i].Name
+			} else {
+				ok = match(k, cols[i].Name)
+			}
+			if ok {
+				fieldNames[i] = k
+				// remove found field
+				l := len(fields) - 1
+				if l > 0 {
+					fields[j] = fields[l]
+				}
+				fields = fields[:l]
+				break
+			}
+		}
+	}
+
+	return fieldNames
+}
+
+// StructOf returns the struct dest points to.
+func StructOf(dest interface{}) (reflect.Value, error) {
+	if dest == nil {
+		return reflect.Value{}, ErrDestNil
+	}
+
+	// check for pointer
+	t := reflect.TypeOf(dest)
+	if k := t.Kind(); k != reflect.Ptr {
+		return reflect.Value{}, ErrNotPointer
+	}
+
+	// see if dest points to nothing
+	sval := reflect.ValueOf(dest)
+	if sval.IsNil() {
+		return reflect.Value{}, ErrDestNil
+	}
+
+	// get handle to struct after we're sure dest is a valid pointer
+	structData := sval.Elem()
+	if k := structData.Kind(); k != reflect.Struct {
+		return reflect.Value{}, ErrNotStruct
+	}
+
+	// no destination fields, return
+	if structData.NumField() < 1 {
+		return reflect.Value{}, ErrEmptyStruct
+	}
+
+	return structData, nil
+}
@@ -0,0 +1,73 @@
+package core_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+type base struct {
+	ID int64
+}
+
+type record struct {
+	base
+	Name    string
+	Comment string
+	hidden  int
+}
+
+func TestFields(t *testing.T) {
+	fields := core.Fields(reflect.TypeOf(record{}))
+	if !reflect.DeepEqual(fields, []string{"ID", "Name", "Comment"}) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cols := []core.Column{{Name: "name"}, {Name: "id"}, {Name: "note"}, {Name: "NAME"}}
+	fields := core.Fields(reflect.TypeOf(record{}))
+
+	names := core.Match(fields, cols, core.MatchFold, map[string]string{"Comment": "note"})
+	if !reflect.DeepEqual(names, []string{"Name", "ID", "Comment", ""}) {
+		t.Errorf("unexpected matches: %q", names)
+	}
+
+	// mapped fields only match their column
+	fields = core.Fields(reflect.TypeOf(record{}))
+	names = core.Match(fields, []core.Column{{Name: "comment"}}, core.MatchFold, map[string]string{"Comment": "note"})
+	if names[0] != "" {
+		t.Errorf("mapped field matched by name: %q", names)
+	}
+
+	if core.MatchFold("", "") || !core.MatchFold("Name", "NAME") {
+		t.Error("unexpected result of MatchFold")
+	}
+}
+
+func TestStructOf(t *testing.T) {
+	var r record
+	v, err := core.StructOf(&r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Type() != reflect.TypeOf(r) {
+		t.Errorf("unexpected type: %v", v.Type())
+	}
+
+	for _, tc := range []struct {
+		dest interface{}
+		err  error
+	}{
+		{nil, core.ErrDestNil},
+		{r, core.ErrNotPointer},
+		{(*record)(nil), core.ErrDestNil},
+		{new(int), core.ErrNotStruct},
+		{&struct{}{}, core.ErrEmptyStruct},
+	} {
+		if _, err := core.StructOf(tc.dest); err != tc.err {
+			t.Errorf("StructOf(%T): unexpected error: %v", tc.dest, err)
+		}
+	}
+}
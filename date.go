@@ -0,0 +1,226 @@
+package pgxscan
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// Date is a calendar date w/o time and time zone, as stored in a date column.
+// Dates are comparable w/ ==.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// Time is a time of day w/o date and time zone, as stored in a time column.
+// Times are comparable w/ ==.
+type Time struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// TimeOfDayDate is the date time columns are put on when assigned to time.Time fields.
+// The time is in the location of TimeOfDayDate, the clock of TimeOfDayDate is ignored.
+var TimeOfDayDate = time.Date(0, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04:05.999999999"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	dateType      = reflect.TypeOf(Date{})
+	timeOfDayType = reflect.TypeOf(Time{})
+)
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalJSON encodes d as "YYYY-MM-DD".
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a "YYYY-MM-DD" string.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	t, err := time.Parse(`"`+dateLayout+`"`, string(b))
+	if err != nil {
+		return err
+	}
+	*d = DateOf(t)
+	return nil
+}
+
+// String formats t as "hh:mm:ss" w/ the fraction of a second if there is one.
+// The end of the day, which Postgres allows, is formatted as "24:00:00".
+func (t Time) String() string {
+	if t == endOfDay {
+		return "24:00:00"
+	}
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format(timeLayout)
+}
+
+// MarshalJSON encodes t as "hh:mm:ss" w/ the fraction of a second if there is one.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a "hh:mm:ss[.fraction]" string, "24:00:00" is the end of the day.
+func (t *Time) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := parseClock(timeLayout, s)
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// endOfDay is the time 24:00:00, Postgres allows it for time and timetz.
+var endOfDay = Time{Hour: 24}
+
+// parseClock parses the time of day s w/ layout, like time.Parse.
+// Unlike time.Parse it accepts 24:00:00, the end of the day.
+func parseClock(layout, s string) (Time, error) {
+	if strings.HasPrefix(s, "24:") {
+		// parsed as midnight, anything later is invalid
+		v, err := time.Parse(layout, "00:"+s[3:])
+		if err != nil {
+			return Time{}, err
+		}
+		if v.Minute() != 0 || v.Second() != 0 || v.Nanosecond() != 0 {
+			return Time{}, fmt.Errorf("time %q out of range", s)
+		}
+		return endOfDay, nil
+	}
+	v, err := time.Parse(layout, s)
+	if err != nil {
+		return Time{}, err
+	}
+	return Time{Hour: v.Hour(), Minute: v.Minute(), Second: v.Second(), Nanosecond: v.Nanosecond()}, nil
+}
+
+// assignTimeOfDay assigns the time column value v, in microseconds since midnight, to dest,
+// which has to be a Time or a time.Time.
+func assignTimeOfDay(dest reflect.Value, v int64, fd *pgproto3.FieldDescription) error {
+	if fd.DataTypeOID != pgtype.TimeOID {
+		return ErrInvalidDestination
+	}
+	t := timeOf(time.Duration(v) * time.Microsecond)
+	if dest.Type() == timeType {
+		// wall clock time, also on days w/ DST changes
+		y, m, d := TimeOfDayDate.Date()
+		dest.Set(reflect.ValueOf(time.Date(y, m, d, t.Hour, t.Minute, t.Second, t.Nanosecond, TimeOfDayDate.Location())))
+		return nil
+	}
+	dest.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// timetzOID is the OID of the time with time zone type, pgtype has no support for it.
+const timetzOID = 1266
+
+// TimeTZ is a time of day w/ UTC offset, as stored in a timetz column.
+type TimeTZ struct {
+	Time Time
+	// Offset is the offset to UTC in seconds, east of UTC is positive.
+	Offset int
+}
+
+var timeTZType = reflect.TypeOf(TimeTZ{})
+
+// Location returns a fixed zone w/ the offset of t.
+func (t TimeTZ) Location() *time.Location {
+	return time.FixedZone("", t.Offset)
+}
+
+// assignTimeTZ assigns the timetz value v, which pgx returns undecoded, to the TimeTZ field dest.
+func assignTimeTZ(dest reflect.Value, v interface{}) error {
+	var (
+		tz  TimeTZ
+		err error
+	)
+	switch v := v.(type) {
+	case string:
+		tz, err = parseTimeTZ(v)
+	case []byte:
+		// microseconds since midnight and the offset in seconds west of UTC
+		if len(v) != 12 {
+			return fmt.Errorf("%w: timetz value %x", ErrMalformedValue, v)
+		}
+		us := time.Duration(binary.BigEndian.Uint64(v)) * time.Microsecond
+		tz = TimeTZ{
+			Time:   timeOf(us),
+			Offset: -int(int32(binary.BigEndian.Uint32(v[8:]))),
+		}
+	default:
+		return ErrInvalidDestination
+	}
+	if err != nil {
+		return err
+	}
+	dest.Set(reflect.ValueOf(tz))
+	return nil
+}
+
+// parseTimeTZ parses the text format of timetz, e.g. 13:04:05.123+02 or 08:00:00-03:30.
+func parseTimeTZ(s string) (TimeTZ, error) {
+	i := strings.LastIndexAny(s, "+-")
+	if i < 0 {
+		return TimeTZ{}, fmt.Errorf("%w: timetz value %q", ErrMalformedValue, s)
+	}
+	clock, err := parseClock("15:04:05.999999", s[:i])
+	if err != nil {
+		return TimeTZ{}, fmt.Errorf("%w: timetz value %q", ErrMalformedValue, s)
+	}
+
+	// offset is hh[:mm[:ss]]
+	offset := 0
+	for n, part := range strings.Split(s[i+1:], ":") {
+		v, err := strconv.Atoi(part)
+		if err != nil || n > 2 || len(part) != 2 {
+			return TimeTZ{}, fmt.Errorf("%w: timetz value %q", ErrMalformedValue, s)
+		}
+		offset += v * []int{3600, 60, 1}[n]
+	}
+	if s[i] == '-' {
+		offset = -offset
+	}
+
+	return TimeTZ{
+		Time:   clock,
+		Offset: offset,
+	}, nil
+}
+
+// timeOf splits the duration since midnight d into a Time.
+func timeOf(d time.Duration) Time {
+	return Time{
+		Hour:       int(d / time.Hour),
+		Minute:     int(d % time.Hour / time.Minute),
+		Second:     int(d % time.Minute / time.Second),
+		Nanosecond: int(d % time.Second),
+	}
+}
@@ -0,0 +1,329 @@
+package pgxscan_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructDateTime(t *testing.T) {
+	var dest struct {
+		D pgxscan.Date
+		T pgxscan.Time
+		P *pgxscan.Date
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("d", pgtype.DateOID, time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.D != (pgxscan.Date{Year: 2021, Month: time.February, Day: 28}) {
+		t.Errorf("value mismatch for field D: %v", dest.D)
+	}
+
+	us := int64((13*time.Hour + 4*time.Minute + 5*time.Second + 6*time.Microsecond) / time.Microsecond)
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("t", pgtype.TimeOID, us))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.T != (pgxscan.Time{Hour: 13, Minute: 4, Second: 5, Nanosecond: 6000}) {
+		t.Errorf("value mismatch for field T: %v", dest.T)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("p", pgtype.DateOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P != nil {
+		t.Errorf("value mismatch for field P: %v", dest.P)
+	}
+
+	// bigints are no times
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("t", pgtype.Int8OID, us))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid source not detected, error: %v", err)
+	}
+}
+
+func TestDateTimeJSON(t *testing.T) {
+	v := struct {
+		D pgxscan.Date
+		T pgxscan.Time
+	}{
+		D: pgxscan.Date{Year: 2021, Month: time.March, Day: 1},
+		T: pgxscan.Time{Hour: 8, Minute: 30, Nanosecond: 500000000},
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"D":"2021-03-01","T":"08:30:00.5"}` {
+		t.Errorf("unexpected encoding: %s", b)
+	}
+
+	var w struct {
+		D pgxscan.Date
+		T pgxscan.Time
+	}
+	err = json.Unmarshal(b, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w != v {
+		t.Errorf("value mismatch: %+v, want %+v", w, v)
+	}
+}
+
+func TestTimeEndOfDay(t *testing.T) {
+	// time '24:00:00'
+	var dest struct {
+		T pgxscan.Time
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("t", pgtype.TimeOID, int64(24*time.Hour/time.Microsecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.T != (pgxscan.Time{Hour: 24}) || dest.T.String() != "24:00:00" {
+		t.Errorf("value mismatch for field T: %v", dest.T)
+	}
+
+	b, err := json.Marshal(dest.T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v pgxscan.Time
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+	if v != dest.T {
+		t.Errorf("end of day does not round-trip: %s, %+v", b, v)
+	}
+
+	// nothing after the end of the day
+	if err := json.Unmarshal([]byte(`"24:00:01"`), &v); err == nil {
+		t.Error("24:00:01 accepted")
+	}
+
+	var destTZ struct {
+		T pgxscan.TimeTZ
+	}
+	err = pgxscan.ReadStruct(&destTZ, mkColumnRows("t", 1266, "24:00:00+02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destTZ.T != (pgxscan.TimeTZ{Time: pgxscan.Time{Hour: 24}, Offset: 7200}) {
+		t.Errorf("value mismatch for field T: %+v", destTZ.T)
+	}
+}
+
+func TestReadStructTimeOfDay(t *testing.T) {
+	var dest struct {
+		T  time.Time
+		US int64
+	}
+	us := int64((23*time.Hour + 59*time.Minute) / time.Microsecond)
+	for _, col := range []string{"t", "us"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.TimeOID, us))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !dest.T.Equal(time.Date(0, 1, 1, 23, 59, 0, 0, time.UTC)) {
+		t.Errorf("value mismatch for field T: %v", dest.T)
+	}
+	if dest.US != us {
+		t.Errorf("value mismatch for field US: %v", dest.US)
+	}
+
+	loc := time.FixedZone("X", 3600)
+	pgxscan.TimeOfDayDate = time.Date(2021, 6, 1, 0, 0, 0, 0, loc)
+	defer func() { pgxscan.TimeOfDayDate = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC) }()
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("t", pgtype.TimeOID, us))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.T.Equal(time.Date(2021, 6, 1, 23, 59, 0, 0, loc)) {
+		t.Errorf("value mismatch for field T: %v", dest.T)
+	}
+}
+
+func TestReadStructTimeTZ(t *testing.T) {
+	const timetzOID = 1266
+	var dest struct {
+		T pgxscan.TimeTZ
+		S string
+	}
+	for _, tc := range []struct {
+		v    interface{}
+		want pgxscan.TimeTZ
+	}{
+		{"13:04:05.123+02", pgxscan.TimeTZ{Time: pgxscan.Time{Hour: 13, Minute: 4, Second: 5, Nanosecond: 123000000}, Offset: 7200}},
+		{"08:00:00-03:30", pgxscan.TimeTZ{Time: pgxscan.Time{Hour: 8}, Offset: -12600}},
+		// 01:00:00, 1 hour west of UTC
+		{[]byte{0, 0, 0, 0, 0xd6, 0x93, 0xa4, 0, 0, 0, 0x0e, 0x10}, pgxscan.TimeTZ{Time: pgxscan.Time{Hour: 1}, Offset: -3600}},
+	} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows("t", timetzOID, tc.v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest.T != tc.want {
+			t.Errorf("value mismatch for %v: %+v, want %+v", tc.v, dest.T, tc.want)
+		}
+	}
+	if _, off := time.Date(2000, 1, 1, 0, 0, 0, 0, dest.T.Location()).Zone(); off != -3600 {
+		t.Errorf("unexpected location offset: %d", off)
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("s", timetzOID, "13:04:05+02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S != "13:04:05+02" {
+		t.Errorf("value mismatch for field S: %q", dest.S)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("t", timetzOID, "13:04:05"))
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+}
+
+func TestReadStructTimeLocation(t *testing.T) {
+	var dest struct {
+		T time.Time
+		P *time.Time
+	}
+	ts := time.Date(2021, 3, 1, 12, 0, 0, 0, time.FixedZone("X", 3600))
+
+	pgxscan.TimeLocation = time.UTC
+	defer func() { pgxscan.TimeLocation = nil }()
+	for _, col := range []string{"t", "p"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.TimestamptzOID, ts))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.T.Location() != time.UTC || !dest.T.Equal(ts) {
+		t.Errorf("value not converted: %v", dest.T)
+	}
+	if dest.P == nil || dest.P.Location() != time.UTC {
+		t.Errorf("value not converted: %v", dest.P)
+	}
+
+	// timestamps w/o time zone are not converted
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("t", pgtype.TimestampOID, ts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.T.Location() == time.UTC {
+		t.Errorf("timestamp converted: %v", dest.T)
+	}
+}
+
+func TestReadStructTimeLocationContainers(t *testing.T) {
+	ts := time.Date(2021, 3, 1, 12, 0, 0, 0, time.FixedZone("X", 3600))
+	var arr pgtype.TimestamptzArray
+	if err := arr.Set([]time.Time{ts}); err != nil {
+		t.Fatal(err)
+	}
+	tr := pgtype.Tstzrange{
+		Lower:     pgtype.Timestamptz{Time: ts, Status: pgtype.Present},
+		Upper:     pgtype.Timestamptz{Time: ts.Add(time.Hour), Status: pgtype.Present},
+		LowerType: pgtype.Inclusive,
+		UpperType: pgtype.Exclusive,
+		Status:    pgtype.Present,
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("slice"), DataTypeOID: pgtype.TimestamptzArrayOID},
+			{Name: []byte("ptrs"), DataTypeOID: pgtype.TimestamptzArrayOID},
+			{Name: []byte("array"), DataTypeOID: pgtype.TimestamptzArrayOID},
+			{Name: []byte("booked"), DataTypeOID: pgtype.TstzrangeOID},
+		},
+		vals: []interface{}{arr, arr, arr, tr.Get()},
+	}
+
+	var dest struct {
+		Slice  []time.Time
+		Ptrs   []*time.Time
+		Array  pgxscan.Array[time.Time]
+		Booked pgxscan.Range[time.Time]
+	}
+	pgxscan.TimeLocation = time.UTC
+	defer func() { pgxscan.TimeLocation = nil }()
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dest.Slice[0].Location() != time.UTC {
+		t.Errorf("value not converted for field Slice: %v", dest.Slice)
+	}
+	if dest.Ptrs[0].Location() != time.UTC {
+		t.Errorf("value not converted for field Ptrs: %v", dest.Ptrs[0])
+	}
+	if dest.Array.Elements[0].Location() != time.UTC {
+		t.Errorf("value not converted for field Array: %v", dest.Array.Elements)
+	}
+	if dest.Booked.Lower.Location() != time.UTC || dest.Booked.Upper.Location() != time.UTC {
+		t.Errorf("bounds not converted for field Booked: %v %v", dest.Booked.Lower, dest.Booked.Upper)
+	}
+}
+
+func TestReadStructInfinity(t *testing.T) {
+	var dest struct {
+		T  time.Time
+		P  *time.Time
+		D  pgxscan.Date
+		Pg pgtype.Timestamptz
+	}
+	rows := func(col string, im pgtype.InfinityModifier) testRows {
+		return mkColumnRows(col, pgtype.TimestamptzOID, im)
+	}
+
+	// rejected by default
+	for _, col := range []string{"t", "p", "d"} {
+		err := pgxscan.ReadStruct(&dest, rows(col, pgtype.Infinity))
+		if !errors.Is(err, pgxscan.ErrOutOfRange) {
+			t.Errorf("infinity not detected for %s, error: %v", col, err)
+		}
+	}
+	err := pgxscan.ReadStruct(&dest, rows("pg", pgtype.Infinity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Pg.InfinityModifier != pgtype.Infinity {
+		t.Errorf("value mismatch for field Pg: %v", dest.Pg)
+	}
+
+	defer func() { pgxscan.Infinity = pgxscan.InfinityError }()
+
+	pgxscan.Infinity = pgxscan.InfinitySentinel
+	for col, im := range map[string]pgtype.InfinityModifier{"t": pgtype.NegativeInfinity, "p": pgtype.Infinity, "d": pgtype.Infinity} {
+		err = pgxscan.ReadStruct(&dest, rows(col, im))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !dest.T.Equal(pgxscan.MinTime) || dest.P == nil || !dest.P.Equal(pgxscan.MaxTime) || dest.D.Year != 9999 {
+		t.Errorf("value mismatch: %v %v %v", dest.T, dest.P, dest.D)
+	}
+
+	pgxscan.Infinity = pgxscan.InfinityNil
+	err = pgxscan.ReadStruct(&dest, rows("p", pgtype.Infinity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P != nil {
+		t.Errorf("value mismatch for field P: %v", dest.P)
+	}
+	err = pgxscan.ReadStruct(&dest, rows("t", pgtype.Infinity))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("infinity not detected, error: %v", err)
+	}
+}
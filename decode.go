@@ -0,0 +1,232 @@
+package pgxscan
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// ErrMalformedValue is returned by DecodeValue if the raw value can not be decoded.
+var ErrMalformedValue = errors.New("malformed DB value")
+
+const (
+	textFormat   = 0
+	binaryFormat = 1
+)
+
+// DecodeValue decodes src, the raw value of a column of type oid, and assigns it to dest.
+//
+// format is the wire format of src, 0 for text and 1 for binary.
+// A nil src is NULL.
+// dest has to be a pointer, the value it points to gets assigned following the rules of ReadStruct.
+// ci provides the known data types, if nil the defaults of pgtype are used.
+//
+// DecodeValue does the same decoding pgx does for rows.Values(). It is meant for
+// values obtained by other means, like RawValues, and for testing the assignment rules
+// against arbitrary input. Malformed input results in an error wrapping ErrMalformedValue.
+// Assignment errors are returned as *ScanError, like from ReadStruct.
+func DecodeValue(ci *pgtype.ConnInfo, oid uint32, format int16, src []byte, dest interface{}) error {
+	if dest == nil {
+		return ErrDestNil
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr {
+		return ErrNotPointer
+	}
+	if dv.IsNil() {
+		return ErrDestNil
+	}
+
+	if ci == nil {
+		ci = pgtype.NewConnInfo()
+	}
+	v, err := decodeRaw(ci, oid, format, src)
+	if err != nil {
+		return err
+	}
+	if err := checkSize([]interface{}{v}, nil); err != nil {
+		return err
+	}
+
+	fd := pgproto3.FieldDescription{DataTypeOID: oid, Format: format}
+	return assignField(dv.Elem(), v, &fd, "")
+}
+
+// decodeRaw decodes src the same way pgx does.
+// The decoders of pgtype are not part of pgxscan, panics in there are turned into errors.
+func decodeRaw(ci *pgtype.ConnInfo, oid uint32, format int16, src []byte) (v interface{}, err error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			v = nil
+			err = fmt.Errorf("%w: %v", ErrMalformedValue, r)
+		}
+	}()
+
+	var value pgtype.Value
+	if dt, ok := ci.DataTypeForOID(oid); ok {
+		if format == binaryFormat {
+			err = checkBinary(dt, src)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// fresh value, the one in ci is shared
+		value = pgtype.NewValue(dt.Value)
+	}
+
+	switch format {
+	case textFormat:
+		decoder, ok := value.(pgtype.TextDecoder)
+		if !ok {
+			decoder = &pgtype.GenericText{}
+		}
+		err = decoder.DecodeText(ci, src)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+		}
+		return decoder.(pgtype.Value).Get(), nil
+	case binaryFormat:
+		decoder, ok := value.(pgtype.BinaryDecoder)
+		if !ok {
+			decoder = &pgtype.GenericBinary{}
+		}
+		err = decoder.DecodeBinary(ci, src)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+		}
+		return decoder.(pgtype.Value).Get(), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown format code %d", ErrMalformedValue, format)
+	}
+}
+
+// maxArrayDims is the maximum number of array dimensions Postgres supports.
+const maxArrayDims = 6
+
+// checkBinary validates binary values pgtype does not handle gracefully.
+func checkBinary(dt *pgtype.DataType, src []byte) error {
+	switch {
+	case dt.OID == pgtype.NumericOID:
+		return checkNumeric(src)
+	case strings.HasPrefix(dt.Name, "_"):
+		// array types are named like the element type w/ a leading underscore
+		return checkArray(src)
+	}
+	return nil
+}
+
+// checkArray validates a binary array.
+// pgtype allocates memory based on the header, so it must be consistent with the data.
+// The elements are checked as well, if their type needs it.
+func checkArray(src []byte) error {
+	// number of dimensions, has null flag, element oid
+	if len(src) < 12 {
+		return fmt.Errorf("%w: array header too short", ErrMalformedValue)
+	}
+	ndims := int32(binary.BigEndian.Uint32(src))
+	if ndims < 0 || ndims > maxArrayDims {
+		return fmt.Errorf("%w: invalid number of array dimensions %d", ErrMalformedValue, ndims)
+	}
+	elemOID := binary.BigEndian.Uint32(src[8:])
+	rp := 12
+	if len(src) < rp+int(ndims)*8 {
+		return fmt.Errorf("%w: array header too short", ErrMalformedValue)
+	}
+
+	// every element is preceded by its 4 byte length, which limits the possible number
+	maxElements := int64(len(src)-rp-int(ndims)*8) / 4
+	elements := int64(1)
+	if ndims == 0 {
+		elements = 0
+	}
+	for i := int32(0); i < ndims; i++ {
+		l := int32(binary.BigEndian.Uint32(src[rp:]))
+		rp += 8 // skip lower bound
+		if l < 0 {
+			return fmt.Errorf("%w: invalid array dimension length %d", ErrMalformedValue, l)
+		}
+		elements *= int64(l)
+		if elements > maxElements {
+			return fmt.Errorf("%w: array has more elements than data", ErrMalformedValue)
+		}
+	}
+
+	for i := int64(0); i < elements; i++ {
+		if len(src) < rp+4 {
+			return fmt.Errorf("%w: array has more elements than data", ErrMalformedValue)
+		}
+		l := int32(binary.BigEndian.Uint32(src[rp:]))
+		rp += 4
+		if l < 0 {
+			// NULL
+			continue
+		}
+		if len(src) < rp+int(l) {
+			return fmt.Errorf("%w: array element exceeds data", ErrMalformedValue)
+		}
+		if elemOID == pgtype.NumericOID {
+			err := checkNumeric(src[rp : rp+int(l)])
+			if err != nil {
+				return err
+			}
+		}
+		rp += int(l)
+	}
+
+	return nil
+}
+
+// checkNumeric validates a binary numeric against the invariants Postgres keeps.
+// pgtype loops forever on some inconsistent values.
+func checkNumeric(src []byte) error {
+	// number of digits, weight, sign, display scale
+	if len(src) < 8 {
+		return fmt.Errorf("%w: numeric header too short", ErrMalformedValue)
+	}
+	ndigits := int(int16(binary.BigEndian.Uint16(src)))
+	weight := int(int16(binary.BigEndian.Uint16(src[2:])))
+	sign := binary.BigEndian.Uint16(src[4:])
+	dscale := int(int16(binary.BigEndian.Uint16(src[6:])))
+
+	if ndigits < 0 || len(src) < 8+ndigits*2 {
+		return fmt.Errorf("%w: invalid number of numeric digits", ErrMalformedValue)
+	}
+	switch sign {
+	case 0x0000, 0x4000, 0xC000: // positive, negative, NaN
+	default:
+		return fmt.Errorf("%w: invalid numeric sign", ErrMalformedValue)
+	}
+	if dscale < 0 || dscale > 0x3FFF {
+		return fmt.Errorf("%w: invalid numeric scale", ErrMalformedValue)
+	}
+	if ndigits == 0 {
+		return nil
+	}
+
+	// base 10000 digits, w/o leading or trailing zeros
+	for i := 0; i < ndigits; i++ {
+		d := binary.BigEndian.Uint16(src[8+i*2:])
+		if d > 9999 || (d == 0 && (i == 0 || i == ndigits-1)) {
+			return fmt.Errorf("%w: invalid numeric digit", ErrMalformedValue)
+		}
+	}
+
+	// decimal digits after the point, pgtype computes this as int16
+	// only trailing zeros of the last digit may be cut by the scale
+	frac := (ndigits - weight - 1) * 4
+	if frac < math.MinInt16 || frac > math.MaxInt16 || frac-dscale > 3 {
+		return fmt.Errorf("%w: numeric scale does not match digits", ErrMalformedValue)
+	}
+
+	return nil
+}
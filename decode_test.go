@@ -0,0 +1,134 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func encodeBinary(v pgtype.BinaryEncoder) []byte {
+	b, err := v.EncodeBinary(pgtype.NewConnInfo(), nil)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestDecodeValue(t *testing.T) {
+	var ia pgtype.Int4Array
+	ia.Set([]int32{11, 22})
+
+	var dest []int32
+	err := pgxscan.DecodeValue(nil, pgtype.Int4ArrayOID, 1, encodeBinary(&ia), &dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest, []int32{11, 22}) {
+		t.Errorf("value mismatch: %v", dest)
+	}
+
+	var s *string
+	err = pgxscan.DecodeValue(nil, pgtype.TextOID, 0, []byte("xy"), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == nil || *s != "xy" {
+		t.Errorf("value mismatch: %v", s)
+	}
+
+	// NULL
+	err = pgxscan.DecodeValue(nil, pgtype.TextOID, 0, nil, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != nil {
+		t.Errorf("NULL not assigned as nil: %v", s)
+	}
+
+	// truncated array header
+	err = pgxscan.DecodeValue(nil, pgtype.Int4ArrayOID, 1, encodeBinary(&ia)[:10], &dest)
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+
+	// numeric with negative number of digits, makes pgtype loop forever
+	err = pgxscan.DecodeValue(nil, pgtype.NumericOID, 1, []byte("\xff\x31\x00\x00\x00\x00\x00\x01"), &s)
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed numeric not detected, error: %v", err)
+	}
+
+	var n pgtype.Numeric
+	n.Set("-10000.5")
+	var r *big.Rat
+	err = pgxscan.DecodeValue(nil, pgtype.NumericOID, 1, encodeBinary(&n), &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(big.NewRat(-100005, 10)) != 0 {
+		t.Errorf("value mismatch: %v", r)
+	}
+
+	err = pgxscan.DecodeValue(nil, pgtype.TextOID, 0, []byte("xy"), s)
+	if err != pgxscan.ErrDestNil {
+		t.Errorf("nil destination not detected, error: %v", err)
+	}
+}
+
+func FuzzDecodeValue(f *testing.F) {
+	var ia pgtype.Int4Array
+	ia.Set([]int32{11, 22})
+	var ta pgtype.TextArray
+	ta.Set([]string{"AA", "BB"})
+	var n pgtype.Numeric
+	n.Set("12.5")
+
+	f.Add(uint32(pgtype.Int4ArrayOID), true, encodeBinary(&ia))
+	f.Add(uint32(pgtype.TextArrayOID), true, encodeBinary(&ta))
+	f.Add(uint32(pgtype.TextArrayOID), false, []byte(`{"AA","BB"}`))
+	f.Add(uint32(pgtype.NumericOID), true, encodeBinary(&n))
+	f.Add(uint32(pgtype.JSONBOID), true, []byte("\x01{\"a\":1}"))
+	f.Add(uint32(pgtype.TimestamptzOID), false, []byte("2021-08-17 12:00:00+00"))
+
+	// enum arrays are decoded by pgxscan, not by pgtype
+	const (
+		enumOID      = 16394
+		enumArrayOID = 16393
+	)
+	pgxscan.RegisterEnumArray(enumArrayOID, enumOID)
+	defer pgxscan.RegisterOIDDecoder(enumArrayOID, nil)
+
+	f.Fuzz(func(t *testing.T, oid uint32, binary bool, src []byte) {
+		format := int16(0)
+		if binary {
+			format = 1
+		}
+
+		dests := []interface{}{
+			new(string),
+			new(int64),
+			new(int32),
+			new(*float64),
+			new([]byte),
+			new([]int32),
+			new([]string),
+			new([][]byte),
+			new(*big.Int),
+			new(time.Time),
+			new(pgtype.Numeric),
+			new(struct{ A int }),
+		}
+		for _, d := range dests {
+			// errors are fine, panics are not
+			err := pgxscan.DecodeValue(nil, oid, format, src, d)
+			var serr *pgxscan.ScanError
+			if errors.As(err, &serr) && serr.Panic != nil {
+				t.Fatalf("panic for %T: %v\n%s", d, serr.Panic, serr.Stack)
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+// FieldDiff describes a struct field w/ an unexpected value.
+type FieldDiff struct {
+	Field string
+	Got   interface{}
+	Want  interface{}
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: got %#v, want %#v", d.Field, d.Got, d.Want)
+}
+
+// DiffStruct scans the current record in rows into a new struct of the type want points to
+// and returns the fields that differ from want.
+//
+// Only fields w/ a matching column are compared, values are compared w/ reflect.DeepEqual.
+// It is meant for tests:
+//
+//	diffs, err := pgxscan.DiffStruct(&User{ID: 1, Name: "x"}, rows)
+//	for _, d := range diffs {
+//		t.Error(d)
+//	}
+func DiffStruct(want interface{}, rows PgxRows) ([]FieldDiff, error) {
+	wantData, err := core.StructOf(want)
+	if err != nil {
+		return nil, err
+	}
+
+	got := reflect.New(wantData.Type())
+	err = ReadStruct(got.Interface(), rows)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := ExplainMapping(want, rows.FieldDescriptions())
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []FieldDiff
+	for _, f := range m.Fields {
+		if len(f) < 1 {
+			continue
+		}
+		g := got.Elem().FieldByName(f)
+		if !g.CanInterface() {
+			// not set by ReadStruct either
+			continue
+		}
+		w := wantData.FieldByName(f)
+		if !reflect.DeepEqual(g.Interface(), w.Interface()) {
+			diffs = append(diffs, FieldDiff{Field: f, Got: g.Interface(), Want: w.Interface()})
+		}
+	}
+
+	return diffs, nil
+}
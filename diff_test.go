@@ -0,0 +1,54 @@
+package pgxscan_test
+
+import (
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+func TestDiffStruct(t *testing.T) {
+	type record struct {
+		Bigid    int64
+		String   string
+		Littleid int32
+		Missing  string
+	}
+
+	want := record{Bigid: 703340046535533321, String: "xy", Littleid: 2135533321, Missing: "x"}
+	diffs, err := pgxscan.DiffStruct(&want, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("unexpected diffs: %v", diffs)
+	}
+
+	want.String = "ab"
+	want.Littleid = 1
+	diffs, err = pgxscan.DiffStruct(&want, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("unexpected diffs: %v", diffs)
+	}
+	for _, d := range diffs {
+		switch d.Field {
+		case "String":
+			if d.String() != `String: got "xy", want "ab"` {
+				t.Errorf("unexpected diff: %v", d)
+			}
+		case "Littleid":
+			if d.Got != int32(2135533321) || d.Want != int32(1) {
+				t.Errorf("unexpected diff: %v", d)
+			}
+		default:
+			t.Errorf("unexpected diff: %v", d)
+		}
+	}
+
+	_, err = pgxscan.DiffStruct(want, mkTestRows())
+	if err != pgxscan.ErrNotPointer {
+		t.Errorf("non pointer not detected, error: %v", err)
+	}
+}
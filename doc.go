@@ -19,6 +19,17 @@
 // Neither int64 nor int16 are allowed as destination types for an int result.
 //
 // This applies to all supported types!
+// The exception is CockroachDBMode, which allows bigint results in int32 and int16 fields
+// as long as the value fits.
+// Values can also be assigned to named types w/ the same underlying type, e.g. type UserID int64
+// or type Status string for enum values. bytea values can be assigned to all slices of byte kind.
+// interface{} fields get the value as returned by pgx, other interfaces if the value implements them.
+// char(n) values are padded w/ spaces, set TrimChar to remove the padding.
+// Set ParseText to parse text values into numeric fields and FormatNumbers to format
+// numeric values into string fields.
+// Set CoerceBool to assign integers and text like 't' or 'false' to bool fields.
+// Integer results can be assigned to unsigned fields and to int and int8 fields,
+// values that don't fit return ErrOutOfRange.
 //
 // TODO: decide if larger int types should be allowed to hold smaller results.
 // Does only make sense for ints, floating point values would be hit by rounding/representation problems.
@@ -30,19 +41,229 @@
 //  []float32
 //  []float64
 //  []string
+//  []bool
+//  []time.Time
 //  [][]byte
 //
-// Only 1 dimensional arrays are supported for now.
+// []time.Time holds timestamp, timestamptz and date arrays.
+// []string holds text, varchar and char arrays, TrimChar applies to the elements of char arrays.
+// uuid arrays can be assigned to slices of any [16]byte type, like []uuid.UUID.
+// Integer arrays can also be assigned to []int, []int8 and unsigned slices, every element has to fit.
+// Elements of integer and float4 arrays are widened for slices of larger types,
+// e.g. int4[] can be assigned to []int64 and float4[] to []float64.
+// Slices of named types work too, like []UserID for type UserID int64 or []Tag for type Tag string.
+// Go arrays, like [3]float64, can be used instead of slices if the length of the DB array is fixed,
+// other lengths are rejected.
+// Only 1 dimensional arrays are supported for slices.
+// Use Array fields, like Array[int32], for arrays w/ more dimensions or other lower bounds than 1,
+// they hold the elements flat and the length and lower bound of every dimension.
+// NULL elements are assigned as zero values, unless NullElements is set to NullElementsError.
+// Slices of pointers or Null types, like []*int32 or []Null[string], keep NULL elements.
+// Empty arrays are assigned as empty slices. NULL goes into pointers to slices, like *[]int32,
+// or as nil slice if NilSlices is set.
 // The slices in the struct are overwritten by newly allocated slices.
-// So it does not make sense to pre-allocate anything in there.
+// So it does not make sense to pre-allocate anything in there,
+// unless ReuseSlices is set, which reuses slices w/ enough capacity.
+//
+// Pointers to the supported types, like *string or *int64, can hold nullable columns.
+// NULL is assigned as nil, other values are assigned to a newly allocated target.
+// NULL can not be assigned to non-pointer fields, except to Null fields like Null[int64],
+// which hold the value w/o a pointer and have Valid set to false for NULL.
+// Nullable types of other packages can implement NullAssigner, e.g. by a small wrapper.
+// Set EmptyAsNull to assign empty text values as NULL to fields which can hold NULL.
+//
+// Fields of pgtype types, like pgtype.Text or pgtype.Numeric, receive the value including its NULL status.
+// Fields of pgtype array types, like pgtype.Int8Array, keep the dimensions, lower bounds and element status.
+//
+// Fields implementing sql.Scanner, like sql.NullString or sql.NullTime, are filled by calling Scan
+// with the DB value. This allows models written for database/sql to be reused.
+//
+// bytea values are copied, as pgx reuses its read buffer for the next row.
+// Set ZeroCopyBytes to skip the copy if every record is processed before the next one is read.
+// Bytea columns can be assigned to fields implementing encoding.BinaryUnmarshaler.
+//
+// Inet and cidr columns can be assigned to net.IP, net.IPNet, netip.Addr and netip.Prefix fields.
+// Their arrays can be assigned to slices of these types, like []net.IP or []netip.Addr.
+//
+// Hstore columns can be assigned to map[string]string fields, NULL values become empty strings.
+// To keep NULL values use map[string]*string, NULL values are nil there.
+// The hstore type has to be registered with the connection, see PrepareConn.
+//
+// Interval columns can be assigned to time.Duration fields. A day is 24 hours.
+// Months have no fixed duration, intervals w/ months are rejected unless IntervalMonths
+// is set to MonthsApproximate.
+// Use Interval fields to get all parts of an interval w/o loss.
+// Interval arrays can be assigned to slices of both, like []time.Duration or []Interval.
+//
+// pgx returns timestamptz values in the local time zone, set TimeLocation to get them in another one, e.g. UTC.
+// TimeLocation applies to the elements of timestamptz arrays and the bounds of tstzrange values as well.
+//
+// Infinite dates and timestamps are rejected, unless Infinity is set to assign MinTime and MaxTime
+// or nil to pointer fields.
+//
+// Date and time columns can be assigned to Date and Time fields, which have no time zone.
+// Time columns can also be assigned to int64 fields in microseconds since midnight
+// and to time.Time fields, on the date set in TimeOfDayDate.
+// Timetz columns can be assigned to TimeTZ fields, which keep the UTC offset.
+//
+// Columns of type oid and of the reg* types, like regclass, can be assigned to uint32 fields
+// and to string fields. In text format pgx returns the names for reg* types, which can't
+// be assigned to uint32 fields, cast them to oid instead.
+//
+// pg_lsn columns can be assigned to LSN and other uint64 fields and to string fields.
+//
+// Xml columns can be assigned to string and []byte fields and to types implementing xml.Unmarshaler.
+//
+// Bit and varbit columns can be assigned to []bool, []byte w/ the packed bits and Bitset fields.
+//
+// Point columns can be assigned to Point fields or any struct w/ float64 fields X and Y.
+// The other geometric types have their own types: Box, Circle, Line, Path and Polygon.
+//
+// Range columns (int4range, int8range, numrange, tsrange, tstzrange, daterange) can be assigned
+// to Range fields, e.g. Range[int32] or Range[time.Time].
+//
+// Numeric columns can be assigned to *big.Int, *big.Rat and *big.Float fields without loss of precision.
+// Integer fields accept numerics which fit, values w/ a fractional part are rejected
+// unless NumericRounding is set to RoundTruncate or RoundHalfEven.
+// Set NumericFloats to assign numerics and numeric arrays to float fields and slices w/ loss of precision.
+// Numeric arrays can also be assigned to slices of decimal types w/ a registered converter,
+// which is called for every element.
+//
+// Money columns can be assigned to int64 fields in cents, to the math/big types
+// and to decimal types w/ a registered converter, which receives a pgtype.Numeric.
+// pgx returns money as text formatted according to lc_monetary of the server,
+// the last '.' or ',' followed by one or two digits is taken as decimal separator.
+// For locale independent results cast the column to numeric or use the binary format.
+// A *big.Int field only accepts values without fractional part. NULL is assigned as nil.
+//
+// Columns of type json and jsonb can be assigned to json.RawMessage or []byte fields.
+// pgx decodes JSON values, so the document is re-encoded and formatting or key order
+// of the original may not be preserved.
+// If the destination is a struct, a pointer to a struct or a slice of them, the document is unmarshaled
+// into it using encoding/json. This loads child rows aggregated w/ json_agg in one query, like []Child.
+// NULL, which json_agg returns for no rows, is assigned as nil slice.
 //
 // Embedded structs are supported.
 // If there are duplicate field names, the highest level name is used. Which is the Go rule for access.
 //
+// Decoding raw values
+//
+// DecodeValue decodes a raw value, e.g. from RawValues, and assigns it like ReadStruct does.
+// Binary arrays and numerics are validated first, so malformed input can not make pgtype
+// allocate huge amounts of memory or loop forever.
+//
+// Other destinations
+//
+// Read accepts any pointer. Structs are filled like w/ ReadStruct,
+// other destinations receive the value of a single column result, e.g. for select count(*).
+// Results w/ two or three columns can be read w/o a struct by ScanTuple2, ScanTuple3
+// and the ScanTuples functions.
+// ReadStructs reads all records of a result into a slice of structs, like *[]User,
+// w/o a loop over rows.Next.
+// ScanAll does the same w/o reflection on the caller side and returns a typed slice,
+// e.g. ScanAll[User](rows), single column results work too, like ScanAll[int64](rows).
+// MaxRowBytes limits the size of a single record, MaxResultBytes the size of all records read by a collector.
+// W/ PartialResults the collectors keep the records read before a timeout and return ErrPartialResult.
+// WithEveryNth passes only every k-th record of rows to a collector, e.g. for previews of huge results.
+//
+// A record can only be read once from rows. CaptureRow takes a snapshot which can be
+// passed to ReadStruct again and again.
+// CaptureResult keeps all records of a result in wire format. It can be stored, e.g. in a cache,
+// and read later w/o a connection by the rows returned from its Replay method.
+//
+// Lazy fields, like Lazy[Document], defer decoding expensive columns until Get is called.
+// pgx decodes all values of a record, pass rows through WithConnInfo so ReadStruct can skip those columns.
+// W/ CollectDecodeStats ReadStruct counts the size and decode time of every column per struct type,
+// DecodeStatsOf shows which columns are candidates for Lazy fields.
+//
+// Custom types
+//
+// Values of Postgres types pgx doesn't know, like those of extensions, can be decoded by a function
+// registered w/ RegisterTypeDecoder for the type name or RegisterOIDDecoder for the OID.
+// Type names are resolved by PrepareConn. citext and ltree are decoded into strings by default,
+// ltree values can be assigned to []string fields too, which get the labels of the path.
+//
+// Composite types and their arrays have to be registered w/ the ConnInfo of the connection,
+// see pgtype.NewCompositeType and pgtype.NewArrayType. Their values can then be assigned to structs
+// and slices of structs, the attributes are matched w/ the fields like columns.
+// pgx returns the attributes w/o their types. Register the composite type w/ RegisterComposite,
+// and its array w/ RegisterCompositeArray, to assign attributes like columns of their types,
+// e.g. to unmarshal json attributes into structs.
+//
+// Columns of domain types need nothing special, Postgres reports the OID of the base type
+// for them, so they are assigned like values of the base type.
+//
+// Support for further destination types can be added with RegisterConverter.
+// Packages providing converters for optional types should register them in init,
+// so that a blank import is all a user needs.
+//
+// Enum labels
+//
+// LoadEnums reads the labels of all enum types from the database, RegisterEnum sets them for a single type.
+// Afterwards unknown labels in enum columns return an *EnumError instead of being assigned.
+// LoadEnums registers the enum array types too, RegisterEnumArray does it for a single one.
+// Their values can be assigned to []string and slices of named string types, like []Status.
+//
+// Connection poolers
+//
+// Behind poolers in transaction mode, like PgBouncer, prepared statements can't be used.
+// Wrap the Querier passed to GetRow and LoadEnums w/ WithPoolerCompatibility to run their queries
+// w/ the simple protocol. PrepareConn never prepares statements, CheckAll and CheckAllPool only use
+// the unnamed statement, all of them work behind such poolers as they are.
+//
+// Checking mappings
+//
+// ExplainMapping shows which columns of a result go into which struct fields.
+// Queries registered with RegisterCheck can be validated at startup with CheckAll,
+// so a migration breaking a model is detected before the first scan fails.
+// CheckAllPool does the same w/ a pool, like *pgxpool.Pool.
+//
+// CanAssign tells tools, like linters or code generators, if a Postgres type can be assigned to a Go type
+// and if values may be rejected at runtime, for the current settings or the ones passed as options.
+//
+// In tests DiffStruct compares a record w/ an expected struct and lists the differing fields.
+//
+// Mappings
+//
+// Field to column bindings for types that can't be changed can be loaded from a JSON file
+// with LoadMappings or declared in code with RegisterMapping.
+// A mapped field only matches the column it is mapped to.
+//
+// Struct tags
+//
+// A db tag binds a field to a column, e.g. `db:"user_id"`, `db:"-"` excludes the field.
+// Registered mappings take precedence over tags.
+// The layout option parses a text column into a time.Time field:
+//
+//	Created time.Time `db:"created,layout=2006-01-02 15:04"`
+//
+// The layout takes the rest of the tag, so it must be the last option.
+// The nullvalue option sets the value assigned for NULL to a non-pointer field,
+// e.g. `db:"age,nullvalue=-1"`. It works for string, bool and number fields.
+//
+// Models annotated for other libraries can be used unchanged: db tags of sqlx work as they are,
+// gorm and bun tags are read after SetTagReaders(GormTag, BunTag).
+//
+// Structs generated by protoc-gen-go can be filled w/ ProtobufMode set.
+// Their fields are matched by the names in the protobuf tags and wrapper types,
+// like *wrapperspb.StringValue, hold nullable columns.
+// Date and timestamp columns can be assigned to *timestamppb.Timestamp fields.
+//
+// Post-processing
+//
+// Values can be normalized before they are assigned, e.g. to lower case email addresses,
+// by registering a function for the struct type with RegisterPostProcessor.
+//
 // Default name matching
 //
 // A match is found when the following conditions are met:
 //   - both names are not empty (length > 0)
 //   - the name of the struct field matches the name from the result set (EqualFold)
 //
+// Core
+//
+// Collecting the fields of structs, matching them w/ columns and assigning plain Go values
+// is done by the package core, which depends on the standard library only.
+// It can be used w/ other drivers or in tests w/o pgx, this package binds it to pgx v4.
+//
 package pgxscan
@@ -0,0 +1,213 @@
+package pgxscan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgtype"
+)
+
+// EnumError is returned, wrapped in a *ScanError, if an enum column holds a label
+// that is not registered for its type.
+//
+// That happens when a value is added to the enum in the database
+// before the application knows about it.
+type EnumError struct {
+	// Type is the name of the enum type.
+	Type string
+	// Label is the unknown value.
+	Label string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("unknown label %q for enum %s", e.Label, e.Type)
+}
+
+type enumType struct {
+	name   string
+	labels map[string]bool
+}
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[uint32]*enumType{}
+	// enumArrays maps the OIDs of enum array types to the OIDs of their enum type
+	enumArrays = map[uint32]uint32{}
+)
+
+// RegisterEnum registers the valid labels of the enum type with the given OID.
+//
+// Values of columns w/ a registered enum type are checked before they are assigned,
+// unknown labels return an *EnumError.
+// Registering a type again replaces its labels, no labels remove the check.
+func RegisterEnum(oid uint32, name string, labels ...string) {
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	if len(labels) < 1 {
+		delete(enums, oid)
+		return
+	}
+	et := &enumType{name: name, labels: make(map[string]bool, len(labels))}
+	for _, l := range labels {
+		et.labels[l] = true
+	}
+	enums[oid] = et
+}
+
+// RegisterEnumArray registers the array type w/ the OID arrayOID of the enum type enumOID.
+//
+// pgx doesn't know enum array types and returns their values undecoded.
+// Registered arrays are decoded like text[], so they can be assigned to []string
+// and slices of named string types, like []Status.
+// The labels are checked if they are registered for the enum type.
+func RegisterEnumArray(arrayOID, enumOID uint32) {
+	RegisterOIDDecoder(arrayOID, decodeTextArray)
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+
+	enumArrays[arrayOID] = enumOID
+}
+
+// LoadEnums registers all enum types of the database w/ their labels and array types,
+// see RegisterEnum and RegisterEnumArray.
+//
+// It should be called once at startup, labels added later are reported as unknown.
+// Behind poolers in transaction mode pass q through WithPoolerCompatibility.
+func LoadEnums(ctx context.Context, q Querier) error {
+	rows, err := q.Query(ctx, `SELECT t.oid, t.typname, t.typarray, e.enumlabel
+FROM pg_enum e JOIN pg_type t ON t.oid = e.enumtypid
+ORDER BY t.oid, e.enumsortorder`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	loaded := map[uint32]*enumType{}
+	arrays := map[uint32]uint32{}
+	for rows.Next() {
+		var (
+			oid, arrayOID uint32
+			name, label   string
+		)
+		err = rows.Scan(&oid, &name, &arrayOID, &label)
+		if err != nil {
+			return err
+		}
+		et, ok := loaded[oid]
+		if !ok {
+			et = &enumType{name: name, labels: map[string]bool{}}
+			loaded[oid] = et
+		}
+		et.labels[label] = true
+		if arrayOID != 0 {
+			arrays[arrayOID] = oid
+		}
+	}
+	if err = Finish(rows); err != nil {
+		return err
+	}
+
+	for arrayOID, oid := range arrays {
+		RegisterEnumArray(arrayOID, oid)
+	}
+
+	enumsMu.Lock()
+	defer enumsMu.Unlock()
+	for oid, et := range loaded {
+		enums[oid] = et
+	}
+	return nil
+}
+
+// enumElementOID returns the enum type of the elements of the registered enum array type oid, 0 if it isn't registered.
+func enumElementOID(oid uint32) uint32 {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+
+	return enumArrays[oid]
+}
+
+// enumSample returns a value of the registered enum or enum array type oid as text, for CanAssign.
+func enumSample(oid uint32) (string, bool) {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+
+	enumOID, isArray := enumArrays[oid]
+	if !isArray {
+		enumOID = oid
+	}
+	et, ok := enums[enumOID]
+	if !ok || len(et.labels) == 0 {
+		return "", false
+	}
+	labels := make([]string, 0, len(et.labels))
+	for label := range et.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels) // the same sample every time
+	if isArray {
+		return `{"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(labels[0]) + `"}`, true
+	}
+	return labels[0], true
+}
+
+// checkEnum returns an *EnumError if oid is a registered enum type and v is not one of its labels.
+// The elements of registered enum arrays are checked too.
+func checkEnum(oid uint32, v interface{}) error {
+	enumsMu.RLock()
+	defer enumsMu.RUnlock()
+
+	switch v := v.(type) {
+	case string:
+		return checkLabel(enums[oid], v)
+	case pgtype.TextArray:
+		enumOID, ok := enumArrays[oid]
+		if !ok {
+			return nil
+		}
+		for _, e := range v.Elements {
+			if e.Status != pgtype.Present {
+				continue
+			}
+			if err := checkLabel(enums[enumOID], e.String); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkLabel returns an *EnumError if et is set and s is not one of its labels.
+func checkLabel(et *enumType, s string) error {
+	if et == nil || et.labels[s] {
+		return nil
+	}
+	return &EnumError{Type: et.name, Label: s}
+}
+
+// decodeTextArray decodes arrays w/ text elements unknown to pgx, like enum arrays, into a pgtype.TextArray.
+func decodeTextArray(v interface{}) (interface{}, error) {
+	var a pgtype.TextArray
+	var err error
+	switch v := v.(type) {
+	case string:
+		err = a.DecodeText(nil, []byte(v))
+	case []byte:
+		// pgtype allocates based on the header, validate it first
+		if err := checkArray(v); err != nil {
+			return nil, err
+		}
+		err = a.DecodeBinary(nil, v)
+	default:
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+	return a, nil
+}
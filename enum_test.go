@@ -0,0 +1,72 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+func TestRegisterEnum(t *testing.T) {
+	const statusOID = 16394
+	pgxscan.RegisterEnum(statusOID, "status", "active", "paused")
+	defer pgxscan.RegisterEnum(statusOID, "status")
+
+	type Status string
+	var dest struct {
+		S Status
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("s", statusOID, "paused"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S != "paused" {
+		t.Errorf("value mismatch for field S: %q", dest.S)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("s", statusOID, "deleted"))
+	var ee *pgxscan.EnumError
+	if !errors.As(err, &ee) || ee.Type != "status" || ee.Label != "deleted" {
+		t.Errorf("unknown label not detected, error: %v", err)
+	}
+
+	// NULL is not checked
+	var p struct {
+		S *Status
+	}
+	err = pgxscan.ReadStruct(&p, mkColumnRows("s", statusOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterEnumArray(t *testing.T) {
+	const (
+		statusOID      = 16394
+		statusArrayOID = 16393
+	)
+	pgxscan.RegisterEnum(statusOID, "status", "active", "paused")
+	defer pgxscan.RegisterEnum(statusOID, "status")
+	pgxscan.RegisterEnumArray(statusArrayOID, statusOID)
+	defer pgxscan.RegisterOIDDecoder(statusArrayOID, nil)
+
+	type Status string
+	var dest struct {
+		S []Status
+	}
+	// pgx returns the text format of unknown types as string
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("s", statusArrayOID, "{active,paused}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.S, []Status{"active", "paused"}) {
+		t.Errorf("value mismatch for field S: %v", dest.S)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("s", statusArrayOID, "{active,deleted}"))
+	var ee *pgxscan.EnumError
+	if !errors.As(err, &ee) || ee.Label != "deleted" {
+		t.Errorf("unknown label not detected, error: %v", err)
+	}
+}
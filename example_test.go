@@ -67,3 +67,17 @@ func Example() {
 // Output:
 // data before: {String: X:[] Bigid:0 N:0 R:0 Xx:[] A:[] Xa:[]}
 // data after: {String:xy X:[1 2 3] Bigid:7 N:42.1 R:-1e-06 Xx:[[48 49 48 50] [120]] A:[AA BB] Xa:[11 22]}
+
+func ExamplePrepareConn() {
+	ctx := context.Background()
+
+	db := setupDB()
+	defer db.Close(ctx)
+
+	// make extension types like hstore known to the connection
+	// with pgxpool use config.AfterConnect = pgxscan.PrepareConn
+	err := pgxscan.PrepareConn(ctx, db)
+	if err != nil {
+		panic(err)
+	}
+}
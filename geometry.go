@@ -0,0 +1,112 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgtype"
+)
+
+// Point is a point as stored in a point column.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Box is a rectangle as stored in a box column.
+// Postgres stores the upper right and the lower left corner.
+type Box struct {
+	High Point
+	Low  Point
+}
+
+// Circle is a circle as stored in a circle column.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Line is an infinite line as stored in a line column, the points for which A*x + B*y + C = 0.
+type Line struct {
+	A float64
+	B float64
+	C float64
+}
+
+// Path is a path as stored in a path column.
+type Path struct {
+	Points []Point
+	Closed bool
+}
+
+// Polygon is a polygon as stored in a polygon column.
+type Polygon []Point
+
+var (
+	pointType   = reflect.TypeOf(Point{})
+	boxType     = reflect.TypeOf(Box{})
+	circleType  = reflect.TypeOf(Circle{})
+	lineType    = reflect.TypeOf(Line{})
+	pathType    = reflect.TypeOf(Path{})
+	polygonType = reflect.TypeOf(Polygon{})
+)
+
+// assignGeometry assigns the geometric value v to dest, which has to be the matching pgxscan type.
+// Other destinations, like the pgtype types, are assigned directly.
+func assignGeometry(dest reflect.Value, v interface{}) error {
+	var gv interface{}
+	switch v := v.(type) {
+	case pgtype.Box:
+		if dest.Type() == boxType {
+			gv = Box{High: Point(v.P[0]), Low: Point(v.P[1])}
+		}
+	case pgtype.Circle:
+		if dest.Type() == circleType {
+			gv = Circle{Center: Point(v.P), Radius: v.R}
+		}
+	case pgtype.Line:
+		if dest.Type() == lineType {
+			gv = Line{A: v.A, B: v.B, C: v.C}
+		}
+	case pgtype.Path:
+		if dest.Type() == pathType {
+			gv = Path{Points: points(v.P), Closed: v.Closed}
+		}
+	case pgtype.Polygon:
+		if dest.Type() == polygonType {
+			gv = Polygon(points(v.P))
+		}
+	}
+	if gv == nil {
+		return core.Assign(dest, reflect.ValueOf(v))
+	}
+	dest.Set(reflect.ValueOf(gv))
+	return nil
+}
+
+func points(vs []pgtype.Vec2) []Point {
+	ps := make([]Point, len(vs))
+	for i, v := range vs {
+		ps[i] = Point(v)
+	}
+	return ps
+}
+
+// assignPoint assigns p to dest, which has to be a struct w/ float64 fields X and Y.
+func assignPoint(dest reflect.Value, p pgtype.Vec2) error {
+	if dest.Kind() != reflect.Struct {
+		return ErrInvalidDestination
+	}
+	x := dest.FieldByName("X")
+	y := dest.FieldByName("Y")
+	if !isSettableFloat64(x) || !isSettableFloat64(y) {
+		return ErrInvalidDestination
+	}
+	x.SetFloat(p.X)
+	y.SetFloat(p.Y)
+	return nil
+}
+
+func isSettableFloat64(v reflect.Value) bool {
+	return v.IsValid() && v.Kind() == reflect.Float64 && v.CanSet()
+}
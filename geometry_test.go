@@ -0,0 +1,126 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func mkGeometry(v interface {
+	DecodeText(*pgtype.ConnInfo, []byte) error
+	Get() interface{}
+}, s string) interface{} {
+	err := v.DecodeText(nil, []byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return v.Get()
+}
+
+func TestReadStructPoint(t *testing.T) {
+	type location struct {
+		X, Y float64
+		Name string
+	}
+	var dest struct {
+		P pgxscan.Point
+		L location
+		N *pgxscan.Point
+	}
+	p := mkGeometry(&pgtype.Point{}, "(1.5,-2)")
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("p", pgtype.PointOID, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P != (pgxscan.Point{X: 1.5, Y: -2}) {
+		t.Errorf("value mismatch for field P: %v", dest.P)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("l", pgtype.PointOID, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.L.X != 1.5 || dest.L.Y != -2 {
+		t.Errorf("value mismatch for field L: %v", dest.L)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("n", pgtype.PointOID, p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.N == nil || *dest.N != dest.P {
+		t.Errorf("value mismatch for field N: %v", dest.N)
+	}
+
+	var bad struct {
+		P struct{ X, Y int }
+	}
+	err = pgxscan.ReadStruct(&bad, mkColumnRows("p", pgtype.PointOID, p))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}
+
+func TestReadStructGeometry(t *testing.T) {
+	var dest struct {
+		B  pgxscan.Box
+		C  pgxscan.Circle
+		L  pgxscan.Line
+		Pa pgxscan.Path
+		Po pgxscan.Polygon
+		Pg pgtype.Box
+	}
+	box := mkGeometry(&pgtype.Box{}, "(2,3),(0,1)")
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("b"), DataTypeOID: pgtype.BoxOID},
+			{Name: []byte("c"), DataTypeOID: pgtype.CircleOID},
+			{Name: []byte("l"), DataTypeOID: pgtype.LineOID},
+			{Name: []byte("pa"), DataTypeOID: pgtype.PathOID},
+			{Name: []byte("po"), DataTypeOID: pgtype.PolygonOID},
+			{Name: []byte("pg"), DataTypeOID: pgtype.BoxOID},
+		},
+		vals: []interface{}{
+			box,
+			mkGeometry(&pgtype.Circle{}, "<(1,2),3>"),
+			mkGeometry(&pgtype.Line{}, "{1,-1,0}"),
+			mkGeometry(&pgtype.Path{}, "[(0,0),(1,1)]"),
+			mkGeometry(&pgtype.Polygon{}, "((0,0),(1,0),(0,1))"),
+			box,
+		},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dest.B != (pgxscan.Box{High: pgxscan.Point{X: 2, Y: 3}, Low: pgxscan.Point{X: 0, Y: 1}}) {
+		t.Errorf("value mismatch for field B: %v", dest.B)
+	}
+	if dest.C != (pgxscan.Circle{Center: pgxscan.Point{X: 1, Y: 2}, Radius: 3}) {
+		t.Errorf("value mismatch for field C: %v", dest.C)
+	}
+	if dest.L != (pgxscan.Line{A: 1, B: -1, C: 0}) {
+		t.Errorf("value mismatch for field L: %v", dest.L)
+	}
+	if !reflect.DeepEqual(dest.Pa, pgxscan.Path{Points: []pgxscan.Point{{X: 0, Y: 0}, {X: 1, Y: 1}}}) {
+		t.Errorf("value mismatch for field Pa: %v", dest.Pa)
+	}
+	if !reflect.DeepEqual(dest.Po, pgxscan.Polygon{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}) {
+		t.Errorf("value mismatch for field Po: %v", dest.Po)
+	}
+	if dest.Pg != box {
+		t.Errorf("value mismatch for field Pg: %v", dest.Pg)
+	}
+
+	var bad struct {
+		B pgxscan.Circle
+	}
+	err = pgxscan.ReadStruct(&bad, mkColumnRows("b", pgtype.BoxOID, box))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgtype"
+)
+
+// assignHstore assigns the decoded hstore m to dest, which has to be a map w/ string keys
+// and string or *string values.
+// NULL values are assigned as empty strings or nil pointers.
+func assignHstore(dest reflect.Value, m map[string]pgtype.Text) error {
+	t := dest.Type()
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return ErrInvalidDestination
+	}
+	elem := t.Elem()
+	ptr := elem.Kind() == reflect.Ptr
+	if ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.String {
+		return ErrInvalidDestination
+	}
+
+	res := reflect.MakeMapWithSize(t, len(m))
+	for k, v := range m {
+		ev := reflect.ValueOf(v.String).Convert(elem)
+		if ptr {
+			if v.Status == pgtype.Null {
+				ev = reflect.Zero(t.Elem())
+			} else {
+				p := reflect.New(elem)
+				p.Elem().Set(ev)
+				ev = p
+			}
+		}
+		res.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), ev)
+	}
+	dest.Set(res)
+	return nil
+}
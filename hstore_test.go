@@ -0,0 +1,75 @@
+package pgxscan_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func mkHstore(s string) interface{} {
+	var h pgtype.Hstore
+	err := h.DecodeText(nil, []byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return h.Get()
+}
+
+func TestReadStructHstore(t *testing.T) {
+	type attrs map[string]string
+
+	rows := mkColumnRows("attrs", 0, mkHstore(`"a"=>"1", "b"=>NULL`))
+
+	var dest struct {
+		Attrs map[string]string
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Attrs, map[string]string{"a": "1", "b": ""}) {
+		t.Errorf("value mismatch for field Attrs: %v", dest.Attrs)
+	}
+
+	var destB struct {
+		Attrs attrs
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(destB.Attrs, attrs{"a": "1", "b": ""}) {
+		t.Errorf("value mismatch for field Attrs: %v", destB.Attrs)
+	}
+
+	var destC struct {
+		Attrs map[string]int
+	}
+	err = pgxscan.ReadStruct(&destC, rows)
+	if err == nil {
+		t.Error("failed to detect invalid destination type")
+	}
+}
+
+func TestReadStructHstoreNull(t *testing.T) {
+	rows := mkColumnRows("attrs", 0, mkHstore(`"a"=>"1", "b"=>NULL`))
+
+	var dest struct {
+		Attrs map[string]*string
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.Attrs) != 2 {
+		t.Fatalf("value mismatch for field Attrs: %v", dest.Attrs)
+	}
+	if a := dest.Attrs["a"]; a == nil || *a != "1" {
+		t.Errorf("value mismatch for key a: %v", a)
+	}
+	if b, ok := dest.Attrs["b"]; !ok || b != nil {
+		t.Errorf("NULL value not kept for key b: %v", b)
+	}
+}
@@ -0,0 +1,50 @@
+package pgxscan
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+)
+
+var (
+	netIPType     = reflect.TypeOf(net.IP{})
+	netIPNetType  = reflect.TypeOf(net.IPNet{})
+	netipAddrType = reflect.TypeOf(netip.Addr{})
+	netipPrefType = reflect.TypeOf(netip.Prefix{})
+)
+
+// assignInet assigns an inet or cidr value to dest.
+// dest has to be one of net.IP, net.IPNet, netip.Addr or netip.Prefix.
+func assignInet(dest reflect.Value, n *net.IPNet) error {
+	switch dest.Type() {
+	case netIPType:
+		dest.Set(reflect.ValueOf(n.IP))
+	case netIPNetType:
+		dest.Set(reflect.ValueOf(*n))
+	case netipAddrType:
+		addr, ok := netip.AddrFromSlice(n.IP)
+		if !ok {
+			return ErrInvalidDestination
+		}
+		dest.Set(reflect.ValueOf(addr.Unmap()))
+	case netipPrefType:
+		addr, ok := netip.AddrFromSlice(n.IP)
+		if !ok {
+			return ErrInvalidDestination
+		}
+		addr = addr.Unmap()
+		ones, bits := n.Mask.Size()
+		if bits == 0 {
+			// non-canonical mask
+			return ErrInvalidDestination
+		}
+		// an IPv4 address may come w/ a 128 bit mask
+		if addr.Is4() && bits == 128 {
+			ones -= 96
+		}
+		dest.Set(reflect.ValueOf(netip.PrefixFrom(addr, ones)))
+	default:
+		return ErrInvalidDestination
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package pgxscan_test
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructInet(t *testing.T) {
+	var inet pgtype.Inet
+	inet.DecodeText(nil, []byte("192.168.1.5/24"))
+	var cidr pgtype.CIDR
+	cidr.DecodeText(nil, []byte("2001:db8::/32"))
+
+	fds := []pgproto3.FieldDescription{
+		{Name: []byte("ip"), DataTypeOID: pgtype.InetOID},
+		{Name: []byte("ipnet"), DataTypeOID: pgtype.InetOID},
+		{Name: []byte("addr"), DataTypeOID: pgtype.InetOID},
+		{Name: []byte("prefix"), DataTypeOID: pgtype.InetOID},
+		{Name: []byte("net6"), DataTypeOID: pgtype.CIDROID},
+		{Name: []byte("nullnet"), DataTypeOID: pgtype.CIDROID},
+	}
+	rows := testRows{
+		fds:  fds,
+		vals: []interface{}{inet.Get(), inet.Get(), inet.Get(), inet.Get(), cidr.Get(), nil},
+	}
+
+	var dest struct {
+		IP      net.IP
+		IPNet   net.IPNet
+		Addr    netip.Addr
+		Prefix  netip.Prefix
+		Net6    *netip.Prefix
+		NullNet *net.IPNet
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.IP.Equal(net.ParseIP("192.168.1.5")) {
+		t.Errorf("value mismatch for field IP: %v", dest.IP)
+	}
+	if dest.IPNet.String() != "192.168.1.5/24" {
+		t.Errorf("value mismatch for field IPNet: %v", dest.IPNet.String())
+	}
+	if dest.Addr != netip.MustParseAddr("192.168.1.5") {
+		t.Errorf("value mismatch for field Addr: %v", dest.Addr)
+	}
+	if dest.Prefix != netip.MustParsePrefix("192.168.1.5/24") {
+		t.Errorf("value mismatch for field Prefix: %v", dest.Prefix)
+	}
+	if dest.Net6 == nil || *dest.Net6 != netip.MustParsePrefix("2001:db8::/32") {
+		t.Errorf("value mismatch for field Net6: %v", dest.Net6)
+	}
+	if dest.NullNet != nil {
+		t.Errorf("NULL not assigned as nil: %v", dest.NullNet)
+	}
+
+	var destB struct {
+		IP string
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err == nil {
+		t.Error("failed to detect invalid destination type")
+	}
+}
+
+func TestReadStructInetSlice(t *testing.T) {
+	var arr pgtype.InetArray
+	err := arr.DecodeText(nil, []byte("{10.0.0.1,::1,192.168.0.0/24}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := mkColumnRows("acl", pgtype.InetArrayOID, arr)
+
+	var dest struct {
+		ACL []net.IP
+	}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.ACL) != 3 || !dest.ACL[0].Equal(net.ParseIP("10.0.0.1")) || !dest.ACL[1].Equal(net.IPv6loopback) {
+		t.Errorf("value mismatch for field ACL: %v", dest.ACL)
+	}
+
+	var destA struct {
+		ACL []netip.Addr
+	}
+	err = pgxscan.ReadStruct(&destA, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1"), netip.MustParseAddr("192.168.0.0")}
+	if !reflect.DeepEqual(destA.ACL, want) {
+		t.Errorf("value mismatch for field ACL: %v", destA.ACL)
+	}
+}
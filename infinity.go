@@ -0,0 +1,80 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgtype"
+)
+
+// InfinityPolicy decides how infinite date and timestamp values are assigned.
+// Go has no representation for them.
+type InfinityPolicy int
+
+const (
+	// InfinityError rejects infinite values, an error wrapping ErrOutOfRange is returned.
+	InfinityError InfinityPolicy = iota
+	// InfinitySentinel assigns MinTime for -infinity and MaxTime for infinity.
+	InfinitySentinel
+	// InfinityNil assigns nil to pointer fields, other fields get an error like w/ InfinityError.
+	InfinityNil
+)
+
+var (
+	// Infinity is the policy for infinite date and timestamp values.
+	Infinity = InfinityError
+
+	// MinTime is assigned for -infinity w/ InfinitySentinel.
+	MinTime = time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// MaxTime is assigned for infinity w/ InfinitySentinel.
+	MaxTime = time.Date(9999, time.December, 31, 23, 59, 59, 999999999, time.UTC)
+)
+
+// assignInfinity assigns the infinite value im according to Infinity.
+// It returns false if dest is left to the normal rules, like for pgtype fields or
+// pointers which are allocated first.
+func assignInfinity(dest reflect.Value, im pgtype.InfinityModifier) (bool, error) {
+	if dest.CanAddr() {
+		if _, ok := dest.Addr().Interface().(pgtype.Value); ok {
+			// pgtype types represent infinity themselves
+			return false, nil
+		}
+	}
+
+	switch {
+	case Infinity == InfinityNil && dest.Kind() == reflect.Ptr:
+		dest.Set(reflect.Zero(dest.Type()))
+		return true, nil
+	case Infinity == InfinitySentinel && dest.Kind() == reflect.Ptr:
+		return false, nil
+	case Infinity == InfinitySentinel && (dest.Type() == timeType || dest.Type() == dateType):
+		t := MaxTime
+		if im == pgtype.NegativeInfinity {
+			t = MinTime
+		}
+		if dest.Type() == dateType {
+			dest.Set(reflect.ValueOf(DateOf(t)))
+		} else {
+			dest.Set(reflect.ValueOf(t))
+		}
+		return true, nil
+	case dest.Kind() == reflect.Ptr:
+		return false, nil
+	}
+	return true, fmt.Errorf("%w: %v", ErrOutOfRange, im)
+}
+
+// elementTime returns the time of an array element.
+// Infinite elements are MinTime or MaxTime w/ InfinitySentinel, otherwise rejected.
+func elementTime(t time.Time, im pgtype.InfinityModifier) (time.Time, error) {
+	switch {
+	case im == pgtype.None:
+		return t, nil
+	case Infinity != InfinitySentinel:
+		return time.Time{}, fmt.Errorf("%w: %v", ErrOutOfRange, im)
+	case im == pgtype.NegativeInfinity:
+		return MinTime, nil
+	}
+	return MaxTime, nil
+}
@@ -0,0 +1,70 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isUint checks for unsigned integer kinds.
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// assignUint assigns the integer v to the unsigned field dest.
+// Negative values and values too large for dest are rejected.
+func assignUint(dest reflect.Value, v int64) error {
+	if v < 0 || dest.OverflowUint(uint64(v)) {
+		return fmt.Errorf("%w: %d does not fit into %s", ErrOutOfRange, v, dest.Type())
+	}
+	dest.SetUint(uint64(v))
+	return nil
+}
+
+// isPlainInt checks for int and int8, which no integer column matches exactly.
+func isPlainInt(v reflect.Value) bool {
+	k := v.Kind()
+	return k == reflect.Int || k == reflect.Int8
+}
+
+// assignInt assigns the integer v to the int or int8 field dest.
+// Values too large for dest are rejected.
+func assignInt(dest reflect.Value, v int64) error {
+	if dest.OverflowInt(v) {
+		return fmt.Errorf("%w: %d does not fit into %s", ErrOutOfRange, v, dest.Type())
+	}
+	dest.SetInt(v)
+	return nil
+}
+
+// isCheckedIntSlice checks for slices of int, int8 and unsigned integers.
+// No integer array matches them exactly, so every element is checked.
+func isCheckedIntSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	e := reflect.New(v.Type().Elem()).Elem()
+	return isPlainInt(e) || isUint(e)
+}
+
+// assignIntSlice assigns ints to a new slice for dest.
+// Negative values for unsigned elements and values too large for the elements are rejected.
+func assignIntSlice(dest reflect.Value, ints []int64) error {
+	res := makeSlice(dest, len(ints))
+	for i, n := range ints {
+		var err error
+		if e := res.Index(i); isUint(e) {
+			err = assignUint(e, n)
+		} else {
+			err = assignInt(e, n)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	dest.Set(res)
+	return nil
+}
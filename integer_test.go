@@ -0,0 +1,115 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructUint(t *testing.T) {
+	type ID uint64
+	var dest struct {
+		Bigid        ID
+		LittleId     uint
+		VeryLittleId uint16
+	}
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != 703340046535533321 || dest.LittleId != 2135533321 || dest.VeryLittleId != 16384 {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	var small struct {
+		U uint8
+	}
+	err = pgxscan.ReadStruct(&small, mkColumnRows("u", pgtype.Int2OID, int16(255)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.U != 255 {
+		t.Errorf("value mismatch for field U: %v", small.U)
+	}
+
+	err = pgxscan.ReadStruct(&small, mkColumnRows("u", pgtype.Int2OID, int16(256)))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("bigid", pgtype.Int8OID, int64(-1)))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("negative value not detected, error: %v", err)
+	}
+}
+
+func TestReadStructPlainInt(t *testing.T) {
+	var dest struct {
+		Bigid        int
+		LittleId     int
+		VeryLittleId int
+	}
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != 703340046535533321 || dest.LittleId != 2135533321 || dest.VeryLittleId != 16384 {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	var small struct {
+		I int8
+	}
+	err = pgxscan.ReadStruct(&small, mkColumnRows("i", pgtype.Int2OID, int16(-128)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if small.I != -128 {
+		t.Errorf("value mismatch for field I: %v", small.I)
+	}
+	err = pgxscan.ReadStruct(&small, mkColumnRows("i", pgtype.Int2OID, int16(128)))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+}
+
+func TestReadStructIntSlice(t *testing.T) {
+	rows := mkColumnRows("ids", pgtype.Int4ArrayOID, mkArray(&pgtype.Int4Array{}, []int32{1, 300, 70000}))
+
+	var dest struct {
+		IDs []int
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.IDs, []int{1, 300, 70000}) {
+		t.Errorf("value mismatch for field IDs: %v", dest.IDs)
+	}
+
+	var destU struct {
+		IDs []uint
+	}
+	err = pgxscan.ReadStruct(&destU, mkColumnRows("ids", pgtype.Int8ArrayOID, mkArray(&pgtype.Int8Array{}, []int64{0, 42})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(destU.IDs, []uint{0, 42}) {
+		t.Errorf("value mismatch for field IDs: %v", destU.IDs)
+	}
+
+	err = pgxscan.ReadStruct(&destU, mkColumnRows("ids", pgtype.Int2ArrayOID, mkArray(&pgtype.Int2Array{}, []int16{1, -1})))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("failed to detect negative element: %v", err)
+	}
+
+	var destS struct {
+		IDs []uint16
+	}
+	err = pgxscan.ReadStruct(&destS, rows)
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("failed to detect element overflow: %v", err)
+	}
+}
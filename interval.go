@@ -0,0 +1,93 @@
+package pgxscan
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/jackc/pgtype"
+)
+
+// MonthPolicy decides how the months of an interval are handled for time.Duration fields.
+// Months have no fixed duration.
+type MonthPolicy int
+
+const (
+	// MonthsError rejects intervals w/ months, ErrInvalidDestination is returned.
+	MonthsError MonthPolicy = iota
+	// MonthsApproximate converts months like Postgres does for EXTRACT(epoch FROM interval),
+	// 12 months are 365.25 days and every other month is 30 days.
+	MonthsApproximate
+)
+
+// IntervalMonths is the policy for intervals w/ months assigned to time.Duration fields.
+var IntervalMonths = MonthsError
+
+// Interval holds a Postgres interval w/o loss.
+// The parts are kept separately, as Postgres does, because months and days have no fixed length.
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+// intervalArrayOID is the OID of interval[], pgtype has no type for it.
+const intervalArrayOID = 1187
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	intervalType = reflect.TypeOf(Interval{})
+)
+
+const (
+	usPerDay   = 24 * 60 * 60 * 1000000
+	usPerMonth = 30 * usPerDay
+	// 365.25 days
+	usPerYear = 36525 * usPerDay / 100
+)
+
+// assignDuration assigns the interval iv to the time.Duration field dest.
+// Days are 24 hours, months are handled according to IntervalMonths.
+func assignDuration(dest reflect.Value, iv pgtype.Interval) error {
+	if iv.Months != 0 && IntervalMonths == MonthsError {
+		return ErrInvalidDestination
+	}
+
+	// sum up in big ints, the parts can overflow a Duration independently
+	us := big.NewInt(iv.Microseconds)
+	us.Add(us, new(big.Int).Mul(big.NewInt(int64(iv.Days)), big.NewInt(usPerDay)))
+	us.Add(us, new(big.Int).Mul(big.NewInt(int64(iv.Months/12)), big.NewInt(usPerYear)))
+	us.Add(us, new(big.Int).Mul(big.NewInt(int64(iv.Months%12)), big.NewInt(usPerMonth)))
+	ns := us.Mul(us, big.NewInt(int64(time.Microsecond)))
+	if !ns.IsInt64() || ns.Int64() == math.MinInt64 {
+		return ErrOutOfRange
+	}
+
+	dest.SetInt(ns.Int64())
+	return nil
+}
+
+// decodeIntervalArray decodes interval arrays, which pgx returns undecoded.
+// The elements are returned as []interface{} of pgtype.Interval, nil for NULL.
+func decodeIntervalArray(v interface{}) (interface{}, error) {
+	a := pgtype.NewArrayType("_interval", pgtype.IntervalOID, func() pgtype.ValueTranscoder { return &pgtype.Interval{} })
+	var err error
+	switch v := v.(type) {
+	case string:
+		err = a.DecodeText(nil, []byte(v))
+	case []byte:
+		// pgtype allocates based on the header, validate it first
+		if err := checkArray(v); err != nil {
+			return nil, err
+		}
+		err = a.DecodeBinary(nil, v)
+	default:
+		return v, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+	return a.Get(), nil
+}
@@ -0,0 +1,133 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func mkInterval(s string) interface{} {
+	var iv pgtype.Interval
+	err := iv.DecodeText(nil, []byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return iv.Get()
+}
+
+func TestReadStructDuration(t *testing.T) {
+	var dest struct {
+		D time.Duration
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("d", pgtype.IntervalOID, mkInterval("1 day 02:03:04.5")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 26*time.Hour + 3*time.Minute + 4500*time.Millisecond; dest.D != want {
+		t.Errorf("value mismatch for field D: %v, want %v", dest.D, want)
+	}
+
+	// months are rejected by default
+	rows := mkColumnRows("d", pgtype.IntervalOID, mkInterval("1 year 1 mon"))
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("interval w/ months not detected, error: %v", err)
+	}
+
+	pgxscan.IntervalMonths = pgxscan.MonthsApproximate
+	defer func() { pgxscan.IntervalMonths = pgxscan.MonthsError }()
+
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (36525*24*time.Hour)/100 + 30*24*time.Hour; dest.D != want {
+		t.Errorf("value mismatch for field D: %v, want %v", dest.D, want)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("d", pgtype.IntervalOID, mkInterval("300 years")))
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+}
+
+func TestReadStructInterval(t *testing.T) {
+	var dest struct {
+		I pgxscan.Interval
+		P *pgxscan.Interval
+	}
+	rows := mkColumnRows("i", pgtype.IntervalOID, mkInterval("1 year 2 mons 3 days 00:00:01"))
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := pgxscan.Interval{Months: 14, Days: 3, Microseconds: 1000000}
+	if dest.I != want {
+		t.Errorf("value mismatch for field I: %+v, want %+v", dest.I, want)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("p", pgtype.IntervalOID, mkInterval("-1 mon")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P == nil || *dest.P != (pgxscan.Interval{Months: -1}) {
+		t.Errorf("value mismatch for field P: %+v", dest.P)
+	}
+}
+
+func TestReadStructIntervalSlice(t *testing.T) {
+	// pgx returns interval[] undecoded, as text in text format
+	rows := mkColumnRows("ttls", 1187, `{"1 day","00:30:00",NULL}`)
+
+	var dest struct {
+		TTLs []*time.Duration
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest.TTLs) != 3 || *dest.TTLs[0] != 24*time.Hour || *dest.TTLs[1] != 30*time.Minute || dest.TTLs[2] != nil {
+		t.Errorf("value mismatch for field TTLs: %v", dest.TTLs)
+	}
+
+	// NULL elements w/ the NullElements policy
+	var destD struct {
+		TTLs  []time.Duration
+		Fixed [3]time.Duration
+	}
+	err = pgxscan.ReadStruct(&destD, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(destD.TTLs, []time.Duration{24 * time.Hour, 30 * time.Minute, 0}) {
+		t.Errorf("value mismatch for field TTLs: %v", destD.TTLs)
+	}
+	err = pgxscan.ReadStruct(&destD, mkColumnRows("fixed", 1187, `{"1 day","00:30:00",NULL}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destD.Fixed != [3]time.Duration{24 * time.Hour, 30 * time.Minute, 0} {
+		t.Errorf("value mismatch for field Fixed: %v", destD.Fixed)
+	}
+	pgxscan.NullElements = pgxscan.NullElementsError
+	err = pgxscan.ReadStruct(&destD, rows)
+	pgxscan.NullElements = pgxscan.NullElementsZero
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NULL element not rejected, error: %v", err)
+	}
+
+	var destI struct {
+		TTLs []pgxscan.Interval
+	}
+	err = pgxscan.ReadStruct(&destI, mkColumnRows("ttls", 1187, `{"1 mon 2 days"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(destI.TTLs, []pgxscan.Interval{{Months: 1, Days: 2}}) {
+		t.Errorf("value mismatch for field TTLs: %v", destI.TTLs)
+	}
+}
@@ -0,0 +1,156 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// Lazy holds the value of a column, which is decoded and assigned to a T when Get is called first.
+//
+// Meant for expensive columns, like big jsonb or bytea values, which are rarely used.
+// Decoding is only skipped if rows provide the raw values and the types of the connection,
+// like rows passed through WithConnInfo or returned by CapturedResult.Replay:
+//
+//	err := pgxscan.ReadStruct(&dest, pgxscan.WithConnInfo(rows, conn.ConnInfo()))
+//
+// W/ other rows pgx has decoded the value already, Lazy only defers its assignment.
+// Options like TimeLocation apply when Get is called.
+// Post processors and the layout and nullvalue options of tags are not applied to Lazy fields.
+// A Lazy is not safe for concurrent use.
+type Lazy[T any] struct {
+	fd    pgproto3.FieldDescription
+	raw   lazyRaw
+	isRaw bool
+	v     interface{}
+	done  bool
+	value T
+	err   error
+}
+
+// Get returns the value, it is decoded and assigned on the first call.
+// Errors are returned like by ReadStruct, later calls return the same error.
+func (l *Lazy[T]) Get() (T, error) {
+	if !l.done {
+		l.done = true
+		if l.isRaw {
+			l.err = DecodeValue(l.raw.ci, l.fd.DataTypeOID, l.fd.Format, l.raw.b, &l.value)
+		} else {
+			l.err = assignField(reflect.ValueOf(&l.value).Elem(), l.v, &l.fd, "")
+		}
+		// not needed anymore
+		l.raw, l.v = lazyRaw{}, nil
+	}
+	return l.value, l.err
+}
+
+// lazySetter is implemented by all Lazy types.
+type lazySetter interface {
+	setLazy(v interface{}, fd *pgproto3.FieldDescription)
+}
+
+func (l *Lazy[T]) setLazy(v interface{}, fd *pgproto3.FieldDescription) {
+	nl := Lazy[T]{fd: *fd}
+	nl.fd.Name = append([]byte(nil), fd.Name...)
+	if r, ok := v.(lazyRaw); ok {
+		nl.raw, nl.isRaw = r, true
+	} else {
+		// pgx reuses the buffer for the next record
+		nl.v = copyValue(v)
+	}
+	*l = nl
+}
+
+// lazyRaw is the undecoded value of a column read into a Lazy field.
+type lazyRaw struct {
+	b  []byte
+	ci *pgtype.ConnInfo
+}
+
+// rawRows are rows providing the raw values of a record and the types to decode them.
+type rawRows interface {
+	RawValues() [][]byte
+	ConnInfo() *pgtype.ConnInfo
+}
+
+// WithConnInfo returns rows providing ci, which has to hold the types of the connection of rows.
+// ReadStruct decodes the records of such rows itself, so it can skip the columns read into Lazy fields.
+func WithConnInfo(rows pgx.Rows, ci *pgtype.ConnInfo) pgx.Rows {
+	return &connInfoRows{Rows: rows, ci: ci}
+}
+
+// connInfoRows adds the types of the connection to rows.
+type connInfoRows struct {
+	pgx.Rows
+	ci *pgtype.ConnInfo
+}
+
+// ConnInfo returns the types of the connection.
+func (r *connInfoRows) ConnInfo() *pgtype.ConnInfo {
+	return r.ci
+}
+
+// lazyColumns reports the columns matched to Lazy fields of the struct v, nil if there are none.
+func lazyColumns(v reflect.Value, fieldNames []string) []bool {
+	var lazy []bool
+	for i, name := range fieldNames {
+		if name == "" {
+			continue
+		}
+		f := v.FieldByName(name)
+		if !f.CanAddr() {
+			continue
+		}
+		if _, ok := f.Addr().Interface().(lazySetter); ok {
+			if lazy == nil {
+				lazy = make([]bool, len(fieldNames))
+			}
+			lazy[i] = true
+		}
+	}
+	return lazy
+}
+
+// readValues returns the values of the current record in rows, like rows.Values.
+// If rows provide the raw values and their types, the columns marked in lazy are not decoded,
+// their values are returned as lazyRaw.
+// The decoded columns are added to the statistics of sr, if it is not nil.
+func readValues(rows PgxRows, lazy []bool, sr *statsRecorder) ([]interface{}, error) {
+	rr, ok := rows.(rawRows)
+	if (lazy == nil && sr == nil) || !ok {
+		if sr != nil {
+			sr.addSizes(rows, lazy)
+		}
+		return rows.Values()
+	}
+
+	fds := rows.FieldDescriptions()
+	raw := rr.RawValues()
+	if len(raw) != len(fds) {
+		return nil, ErrColumnCount
+	}
+	ci := rr.ConnInfo()
+	if ci == nil {
+		ci = pgtype.NewConnInfo()
+	}
+
+	vals := make([]interface{}, len(raw))
+	for i, b := range raw {
+		if lazy != nil && lazy[i] {
+			if b != nil {
+				// pgx reuses the buffer for the next record
+				b = append([]byte{}, b...)
+			}
+			vals[i] = lazyRaw{b: b, ci: ci}
+			continue
+		}
+		v, err := sr.decode(ci, rows, i, b)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
@@ -0,0 +1,101 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructLazy(t *testing.T) {
+	cr := &pgxscan.CapturedResult{
+		Columns: []pgxscan.CapturedColumn{
+			{Name: "id", OID: pgtype.Int8OID},
+			{Name: "doc", OID: pgtype.JSONBOID},
+			{Name: "blob", OID: pgtype.ByteaOID},
+		},
+		Records: [][][]byte{
+			{[]byte("7"), []byte(`{"a":"x"}`), nil},
+			// malformed bytea, only detected by Get
+			{[]byte("8"), []byte(`{"a":"y"}`), []byte(`\xzz`)},
+		},
+	}
+	rows := cr.Replay(nil)
+
+	type document struct {
+		A string `json:"a"`
+	}
+	var dest struct {
+		ID   int64
+		Doc  pgxscan.Lazy[document]
+		Blob pgxscan.Lazy[*[]byte]
+	}
+	rows.Next()
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := dest.Doc.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.ID != 7 || doc.A != "x" {
+		t.Errorf("value mismatch: %d %v", dest.ID, doc)
+	}
+	blob, err := dest.Blob.Get()
+	if err != nil || blob != nil {
+		t.Errorf("NULL not assigned as nil: %v, error: %v", blob, err)
+	}
+
+	rows.Next()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatalf("lazy column decoded by ReadStruct: %v", err)
+	}
+	_, err = dest.Blob.Get()
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected by Get, error: %v", err)
+	}
+	_, err2 := dest.Blob.Get()
+	if err2 != err {
+		t.Errorf("error of the first call not kept: %v", err2)
+	}
+}
+
+func TestReadStructLazyDecoded(t *testing.T) {
+	var dest struct {
+		ID   pgxscan.Lazy[int64]
+		Name pgxscan.Lazy[string]
+	}
+
+	// rows w/o raw values, pgx has decoded the values already
+	err := pgxscan.ReadStruct(&dest, mkPairRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := dest.ID.Get(); err != nil || id != 7 {
+		t.Errorf("value mismatch for field ID: %v, error: %v", id, err)
+	}
+
+	// rows w/ raw values and types
+	err = pgxscan.ReadStruct(&dest, pgxscan.WithConnInfo(&fakeRows{testRows: mkPairRows(), n: 1}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, err := dest.Name.Get(); err != nil || name != "x" {
+		t.Errorf("value mismatch for field Name: %v, error: %v", name, err)
+	}
+
+	var destB struct {
+		Name pgxscan.Lazy[int64]
+	}
+	err = pgxscan.ReadStruct(&destB, mkPairRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = destB.Name.Get()
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected by Get, error: %v", err)
+	}
+}
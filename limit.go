@@ -0,0 +1,164 @@
+package pgxscan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgtype"
+)
+
+var (
+	// ErrRowTooLarge is returned when the values of a row exceed MaxRowBytes.
+	ErrRowTooLarge = errors.New("row exceeds size limit")
+	// ErrResultTooLarge is returned by the collectors when the values of a result exceed MaxResultBytes.
+	ErrResultTooLarge = errors.New("result exceeds size limit")
+)
+
+// MaxRowBytes limits the approximate size of the values of a row.
+// It applies to everything reading values: ReadStruct, Read, the ScanTuple functions,
+// the collectors, DecodeValue and CaptureRow.
+// If the limit is exceeded an error wrapping ErrRowTooLarge is returned and
+// the destination is left untouched.
+//
+// The size counts the bytes of strings, byte slices, array elements, hstore keys and values
+// and JSON documents, fixed size values count 8 bytes. A value <= 0 disables the limit.
+//
+// pgx has decoded the row when the check is done, the limit keeps huge values from
+// being copied into the application.
+var MaxRowBytes = 0
+
+// MaxResultBytes limits the approximate size of all values read by the collectors
+// ReadStructs, ScanAll, ScanTuples2 and ScanTuples3, counted like for MaxRowBytes.
+// If the limit is exceeded the collector stops and returns an error wrapping ErrResultTooLarge.
+// A value <= 0 disables the limit.
+var MaxResultBytes = 0
+
+// sizeTracker sums the sizes of the rows of a result for MaxResultBytes.
+type sizeTracker struct {
+	n int
+}
+
+// checkSize checks the size of the values of a row against MaxRowBytes.
+// If st is not nil the row is added to the result it tracks, which is checked against MaxResultBytes.
+func checkSize(vals []interface{}, st *sizeTracker) error {
+	limitResult := st != nil && MaxResultBytes > 0
+	if MaxRowBytes <= 0 && !limitResult {
+		return nil
+	}
+
+	n := rowSize(vals)
+	if MaxRowBytes > 0 && n > MaxRowBytes {
+		return fmt.Errorf("%w: %d bytes", ErrRowTooLarge, n)
+	}
+	if limitResult {
+		st.n += n
+		if st.n > MaxResultBytes {
+			return fmt.Errorf("%w: %d bytes", ErrResultTooLarge, st.n)
+		}
+	}
+	return nil
+}
+
+// rowSize returns the approximate size of vals in bytes.
+func rowSize(vals []interface{}) int {
+	n := 0
+	for _, v := range vals {
+		n += valueSize(v)
+	}
+	return n
+}
+
+func valueSize(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case lazyRaw:
+		return len(v.b)
+	case pgtype.Numeric:
+		if v.Int == nil {
+			return 8
+		}
+		return len(v.Int.Bits()) * 8
+	case pgtype.Varbit:
+		return len(v.Bytes)
+	case pgtype.Bit:
+		return len(v.Bytes)
+	case pgtype.TextArray:
+		n := 0
+		for _, e := range v.Elements {
+			n += len(e.String)
+		}
+		return n
+	case pgtype.VarcharArray:
+		n := 0
+		for _, e := range v.Elements {
+			n += len(e.String)
+		}
+		return n
+	case pgtype.BPCharArray:
+		n := 0
+		for _, e := range v.Elements {
+			n += len(e.String)
+		}
+		return n
+	case pgtype.ByteaArray:
+		n := 0
+		for _, e := range v.Elements {
+			n += len(e.Bytes)
+		}
+		return n
+	case pgtype.Int2Array:
+		return len(v.Elements) * 2
+	case pgtype.Int4Array:
+		return len(v.Elements) * 4
+	case pgtype.Float4Array:
+		return len(v.Elements) * 4
+	case pgtype.Int8Array:
+		return len(v.Elements) * 8
+	case pgtype.Float8Array:
+		return len(v.Elements) * 8
+	// hstore
+	case map[string]pgtype.Text:
+		n := 0
+		for k, e := range v {
+			n += len(k) + len(e.String)
+		}
+		return n
+	// decoded JSON, composites and arrays decoded by pgxscan
+	case map[string]interface{}:
+		n := 0
+		for k, e := range v {
+			n += len(k) + valueSize(e)
+		}
+		return n
+	case []interface{}:
+		n := 0
+		for _, e := range v {
+			n += valueSize(e)
+		}
+		return n
+	}
+
+	// all other arrays, like pgtype.NumericArray, are sized by their elements
+	if elems, _, ok := arrayElements(v); ok {
+		n := 0
+		for i := 0; i < elems.Len(); i++ {
+			e, err := elementValue(elems.Index(i))
+			if err != nil {
+				return 8 * elems.Len()
+			}
+			n += valueSize(e)
+		}
+		return n
+	}
+	if reflect.ValueOf(v).Kind() == reflect.String {
+		// named string types, like ltree paths
+		return reflect.ValueOf(v).Len()
+	}
+	return 8
+}
@@ -0,0 +1,144 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestMaxRowBytes(t *testing.T) {
+	pgxscan.MaxRowBytes = 100
+	defer func() { pgxscan.MaxRowBytes = 0 }()
+
+	var dest struct {
+		S string
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("s", pgtype.TextOID, strings.Repeat("x", 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest.S = "old"
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("s", pgtype.TextOID, strings.Repeat("x", 101)))
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized row not detected, error: %v", err)
+	}
+	if dest.S != "old" {
+		t.Error("destination modified")
+	}
+
+	// JSON documents count too
+	doc := map[string]interface{}{"a": strings.Repeat("x", 50), "b": []interface{}{strings.Repeat("y", 50)}}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("s", pgtype.JSONBOID, doc))
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized JSON not detected, error: %v", err)
+	}
+
+	// the default test row is small enough
+	pgxscan.MaxRowBytes = 1000
+	var destB struct {
+		Xx [][]byte
+	}
+	err = pgxscan.ReadStruct(&destB, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaxRowBytesValues(t *testing.T) {
+	pgxscan.MaxRowBytes = 100
+	defer func() { pgxscan.MaxRowBytes = 0 }()
+
+	big := strings.Repeat("x", 101)
+	var num pgtype.Numeric
+	num.Set(strings.Repeat("9", 400))
+	vals := map[string]struct {
+		oid uint32
+		v   interface{}
+	}{
+		"varchar[]": {pgtype.VarcharArrayOID, mkArray(&pgtype.VarcharArray{}, []string{big})},
+		"bpchar[]":  {pgtype.BPCharArrayOID, mkArray(&pgtype.BPCharArray{}, []string{big})},
+		"numeric[]": {pgtype.NumericArrayOID, pgtype.NumericArray{
+			Elements:   []pgtype.Numeric{num},
+			Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+			Status:     pgtype.Present,
+		}},
+		"hstore": {0, map[string]pgtype.Text{"k": {String: big, Status: pgtype.Present}}},
+	}
+	for name, c := range vals {
+		var dest struct {
+			S interface{}
+		}
+		err := pgxscan.ReadStruct(&dest, mkColumnRows("s", c.oid, c.v))
+		if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+			t.Errorf("oversized %s not detected, error: %v", name, err)
+		}
+	}
+
+	// every entry point checks the limit
+	var s string
+	err := pgxscan.Read(&s, mkColumnRows("s", pgtype.TextOID, big))
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized row not detected by Read, error: %v", err)
+	}
+	_, _, err = pgxscan.ScanTuple2[string, string](testRows{
+		fds:  mkPairRows().fds,
+		vals: []interface{}{big, "x"},
+	})
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized row not detected by ScanTuple2, error: %v", err)
+	}
+	err = pgxscan.DecodeValue(nil, pgtype.TextOID, 0, []byte(big), &s)
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized value not detected by DecodeValue, error: %v", err)
+	}
+	_, err = pgxscan.CaptureRow(mkColumnRows("s", pgtype.TextOID, big))
+	if !errors.Is(err, pgxscan.ErrRowTooLarge) {
+		t.Errorf("oversized row not detected by CaptureRow, error: %v", err)
+	}
+}
+
+func TestMaxResultBytes(t *testing.T) {
+	pgxscan.MaxResultBytes = 20
+	defer func() { pgxscan.MaxResultBytes = 0 }()
+
+	type pair struct {
+		ID   int64
+		Name string
+	}
+
+	// a pair counts 9 bytes
+	_, err := pgxscan.ScanAll[pair](&fakeRows{testRows: mkPairRows(), n: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := &fakeRows{testRows: mkPairRows(), n: 3}
+	_, err = pgxscan.ScanAll[pair](rows)
+	if !errors.Is(err, pgxscan.ErrResultTooLarge) {
+		t.Errorf("oversized result not detected by ScanAll, error: %v", err)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+
+	var dest []pair
+	err = pgxscan.ReadStructs(&dest, &fakeRows{testRows: mkPairRows(), n: 3})
+	if !errors.Is(err, pgxscan.ErrResultTooLarge) {
+		t.Errorf("oversized result not detected by ReadStructs, error: %v", err)
+	}
+	_, err = pgxscan.ScanTuples2[int64, string](&fakeRows{testRows: mkPairRows(), n: 3})
+	if !errors.Is(err, pgxscan.ErrResultTooLarge) {
+		t.Errorf("oversized result not detected by ScanTuples2, error: %v", err)
+	}
+
+	// single records are not limited
+	var p pair
+	err = pgxscan.ReadStruct(&p, mkPairRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
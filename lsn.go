@@ -0,0 +1,72 @@
+package pgxscan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+// lsnOID is the OID of the pg_lsn type, pgtype has no support for it.
+const lsnOID = 3220
+
+// LSN is a position in the write ahead log, as stored in a pg_lsn column.
+type LSN uint64
+
+// String formats l like Postgres does, e.g. 16/B374D848.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// parseLSN parses the text format of pg_lsn.
+func parseLSN(s string) (LSN, error) {
+	hi, lo, ok := strings.Cut(s, "/")
+	if !ok {
+		return 0, fmt.Errorf("%w: pg_lsn value %q", ErrMalformedValue, s)
+	}
+	h, err := strconv.ParseUint(hi, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: pg_lsn value %q", ErrMalformedValue, s)
+	}
+	l, err := strconv.ParseUint(lo, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: pg_lsn value %q", ErrMalformedValue, s)
+	}
+	return LSN(h<<32 | l), nil
+}
+
+// assignLSN assigns the pg_lsn value v, which pgx returns undecoded, to dest.
+// uint64 fields, like LSN, get the position, string fields the text.
+func assignLSN(dest reflect.Value, v interface{}) error {
+	var (
+		lsn LSN
+		err error
+	)
+	switch v := v.(type) {
+	case string:
+		lsn, err = parseLSN(v)
+	case []byte:
+		if len(v) != 8 {
+			return fmt.Errorf("%w: pg_lsn value %x", ErrMalformedValue, v)
+		}
+		lsn = LSN(binary.BigEndian.Uint64(v))
+	default:
+		return core.Assign(dest, reflect.ValueOf(v))
+	}
+	if err != nil {
+		return err
+	}
+
+	switch dest.Kind() {
+	case reflect.Uint64:
+		dest.SetUint(uint64(lsn))
+	case reflect.String:
+		dest.SetString(lsn.String())
+	default:
+		return ErrInvalidDestination
+	}
+	return nil
+}
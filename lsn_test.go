@@ -0,0 +1,41 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+func TestReadStructLSN(t *testing.T) {
+	const lsnOID = 3220
+	var dest struct {
+		L   pgxscan.LSN
+		U   uint64
+		S   string
+		Bin pgxscan.LSN
+	}
+	for col, v := range map[string]interface{}{
+		"l":   "16/B374D848",
+		"u":   "16/B374D848",
+		"s":   "16/B374D848",
+		"bin": []byte{0, 0, 0, 0x16, 0xb3, 0x74, 0xd8, 0x48},
+	} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, lsnOID, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	const want = 0x16B374D848
+	if dest.L != want || dest.U != want || dest.Bin != want {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+	if dest.S != "16/B374D848" || dest.L.String() != "16/B374D848" {
+		t.Errorf("unexpected text: %q %q", dest.S, dest.L)
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("l", lsnOID, "16-B374D848"))
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+}
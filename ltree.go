@@ -0,0 +1,46 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ltreePath is the value of an ltree column, e.g. "top.science.astronomy".
+type ltreePath string
+
+// decodeLtree decodes ltree values, the type of the ltree extension.
+// The binary format is a version byte followed by the text.
+func decodeLtree(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return ltreePath(v), nil
+	case []byte:
+		if len(v) < 1 || v[0] != 1 {
+			return nil, fmt.Errorf("%w: ltree value %x", ErrMalformedValue, v)
+		}
+		return ltreePath(v[1:]), nil
+	}
+	return v, nil
+}
+
+// assignLtree assigns the path p to dest, string fields get the path, []string fields the labels.
+func assignLtree(dest reflect.Value, p ltreePath) error {
+	switch {
+	case dest.Kind() == reflect.String:
+		dest.SetString(string(p))
+	case isStringSlice(dest):
+		labels := []string{}
+		if len(p) > 0 {
+			labels = strings.Split(string(p), ".")
+		}
+		ls := reflect.MakeSlice(dest.Type(), len(labels), len(labels))
+		for i, l := range labels {
+			ls.Index(i).SetString(l)
+		}
+		dest.Set(ls)
+	default:
+		return ErrInvalidDestination
+	}
+	return nil
+}
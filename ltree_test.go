@@ -0,0 +1,54 @@
+package pgxscan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgproto3/v2"
+)
+
+// internal test, resolving the type name needs a database otherwise
+func TestAssignLtree(t *testing.T) {
+	const ltreeOID = 16600
+	resolveTypeDecoder("ltree", ltreeOID)
+	defer RegisterOIDDecoder(ltreeOID, nil)
+
+	var dest struct {
+		Path   string
+		Labels []string
+		Bin    []string
+		Root   []string
+	}
+	fd := pgproto3.FieldDescription{DataTypeOID: ltreeOID}
+	dv := reflect.ValueOf(&dest).Elem()
+	for name, v := range map[string]interface{}{
+		"Path":   "top.science.astronomy",
+		"Labels": "top.science.astronomy",
+		"Bin":    []byte("\x01top.science"),
+		"Root":   "",
+	} {
+		err := assignField(dv.FieldByName(name), v, &fd, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if dest.Path != "top.science.astronomy" {
+		t.Errorf("value mismatch for field Path: %q", dest.Path)
+	}
+	if !reflect.DeepEqual(dest.Labels, []string{"top", "science", "astronomy"}) {
+		t.Errorf("value mismatch for field Labels: %v", dest.Labels)
+	}
+	if !reflect.DeepEqual(dest.Bin, []string{"top", "science"}) {
+		t.Errorf("value mismatch for field Bin: %v", dest.Bin)
+	}
+	if dest.Root == nil || len(dest.Root) != 0 {
+		t.Errorf("value mismatch for field Root: %v", dest.Root)
+	}
+
+	err := assignField(dv.FieldByName("Bin"), []byte("\x02x"), &fd, "Bin")
+	if !errors.Is(err, ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+}
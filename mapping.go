@@ -0,0 +1,81 @@
+package pgxscan
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+)
+
+var (
+	mappingsMu sync.RWMutex
+	// struct type name -> field name -> column name, from LoadMappings
+	mappings = map[string]map[string]string{}
+	// struct type -> field name -> column name, from RegisterMapping
+	// keyed by type, as types of different packages can have the same name
+	typeMappings = map[reflect.Type]map[string]string{}
+)
+
+// LoadMappings reads field to column mappings from r and registers them.
+//
+// The input is a JSON object keyed by struct type names, as printed by %T w/o pointer.
+// For every type it holds an object mapping field names to column names:
+//
+//	{
+//	  "models.User": {"ID": "user_id", "Email": "mail_address"}
+//	}
+//
+// A mapped field only matches the given column, compared exactly.
+// Fields w/o mapping are matched by name as usual.
+// This allows custom bindings for types that can't be modified, like generated or vendored ones.
+//
+// Mappings for a type replace the ones registered before.
+func LoadMappings(r io.Reader) error {
+	var m map[string]map[string]string
+	err := json.NewDecoder(r).Decode(&m)
+	if err != nil {
+		return err
+	}
+
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+
+	for typeName, fields := range m {
+		mappings[typeName] = fields
+	}
+	return nil
+}
+
+// RegisterMapping registers field to column mappings for the struct type T.
+// The mappings are applied to every scan of T, see LoadMappings.
+// Unlike loaded mappings they are bound to the type itself, not its name,
+// so types of the same name in different packages don't share them.
+//
+//	pgxscan.RegisterMapping[sdk.Account](map[string]string{"ID": "account_id"})
+//
+// Mappings for a type replace the ones registered before.
+func RegisterMapping[T any](fields map[string]string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	m := make(map[string]string, len(fields))
+	for f, c := range fields {
+		m[f] = c
+	}
+
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+
+	typeMappings[t] = m
+}
+
+// mappingFor returns the registered mapping for the struct type t or nil.
+// Mappings registered for the type go before the ones loaded for its name.
+func mappingFor(t reflect.Type) map[string]string {
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+
+	if m, ok := typeMappings[t]; ok {
+		return m
+	}
+	return mappings[t.String()]
+}
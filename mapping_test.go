@@ -0,0 +1,102 @@
+package pgxscan_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+type mappedRecord struct {
+	ID     int64
+	Text   string
+	Little int32
+}
+
+func TestLoadMappings(t *testing.T) {
+	err := pgxscan.LoadMappings(strings.NewReader(`{
+		"pgxscan_test.mappedRecord": {"ID": "bigid", "Text": "string"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dest mappedRecord
+	err = pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.ID != 703340046535533321 {
+		t.Error("value mismatch for field ID")
+	}
+	if dest.Text != "xy" {
+		t.Error("value mismatch for field Text")
+	}
+
+	m, err := pgxscan.ExplainMapping(&dest, mkTestRows().FieldDescriptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.UnmatchedFields) != 1 || m.UnmatchedFields[0] != "Little" {
+		t.Errorf("unexpected unmatched fields: %v", m.UnmatchedFields)
+	}
+
+	err = pgxscan.LoadMappings(strings.NewReader(`{"x": 1}`))
+	if err == nil {
+		t.Error("invalid mapping not detected")
+	}
+}
+
+type vendoredRecord struct {
+	Key   int64
+	Value float32
+}
+
+func TestRegisterMapping(t *testing.T) {
+	fields := map[string]string{"Key": "bigid", "Value": "n"}
+	pgxscan.RegisterMapping[vendoredRecord](fields)
+	// later changes of the map have no effect
+	fields["Key"] = "littleid"
+
+	var dest vendoredRecord
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Key != 703340046535533321 {
+		t.Error("value mismatch for field Key")
+	}
+	if dest.Value != float32(42.1) {
+		t.Error("value mismatch for field Value")
+	}
+}
+
+func TestRegisterMappingSameName(t *testing.T) {
+	// local types in different scopes have the same name, like models.User in two packages
+	a := func() interface{} {
+		type record struct{ Key int64 }
+		pgxscan.RegisterMapping[record](map[string]string{"Key": "bigid"})
+		return &record{}
+	}()
+	b := func() interface{} {
+		type record struct{ Key int32 }
+		return &record{}
+	}()
+	if reflect.TypeOf(a).String() != reflect.TypeOf(b).String() {
+		t.Fatal("test types have different names")
+	}
+
+	err := pgxscan.ReadStruct(a, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the mapping of a would fail for the int32 field of b
+	err = pgxscan.ReadStruct(b, mkTestRows())
+	if err != nil {
+		t.Fatalf("mapping shared by types w/ the same name: %v", err)
+	}
+	if reflect.ValueOf(a).Elem().Field(0).Int() != 703340046535533321 {
+		t.Error("value mismatch for field Key")
+	}
+}
@@ -0,0 +1,88 @@
+package pgxscan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/jackc/pgtype"
+)
+
+// moneyOID is the OID of the money type, pgtype has no support for it.
+const moneyOID = 790
+
+// moneyNumeric converts the value of a money column to a numeric w/ 2 decimal places.
+// Values of other types, e.g. from a registered money type, are returned as is.
+//
+// Binary values are the amount in cents, independent of the locale.
+// Text values are formatted according to lc_monetary of the server, e.g. "$1,234.56" or "-1.234,56 €".
+// All characters but digits and separators are ignored, a minus sign or parenthesis make the value negative.
+// The last '.' or ',' followed by one or two digits is the decimal separator, other separators are
+// taken as thousands separators.
+func moneyNumeric(v interface{}, format int16) (interface{}, error) {
+	var cents int64
+	switch v := v.(type) {
+	case []byte:
+		if format != binaryFormat || len(v) != 8 {
+			return nil, fmt.Errorf("%w: money value %x", ErrMalformedValue, v)
+		}
+		cents = int64(binary.BigEndian.Uint64(v))
+	case string:
+		var err error
+		cents, err = parseMoney(v)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return v, nil
+	}
+	return pgtype.Numeric{Int: big.NewInt(cents), Exp: -2, Status: pgtype.Present}, nil
+}
+
+// parseMoney parses the text representation of a money value and returns the amount in cents.
+func parseMoney(s string) (int64, error) {
+	var (
+		amount     uint64
+		digits     int
+		sepAt      = -1
+		neg        bool
+		overflowed bool
+	)
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			if amount > (math.MaxInt64-9)/10 {
+				overflowed = true
+			}
+			amount = amount*10 + uint64(c-'0')
+			digits++
+		case c == '.' || c == ',':
+			sepAt = digits
+		case c == '-' || c == '(':
+			neg = true
+		}
+	}
+	if digits == 0 || overflowed {
+		return 0, fmt.Errorf("%w: money value %q", ErrMalformedValue, s)
+	}
+
+	scale := uint64(100)
+	if sepAt >= 0 {
+		switch digits - sepAt {
+		case 1:
+			scale = 10
+		case 2:
+			scale = 1
+		}
+	}
+	if amount > math.MaxInt64/scale {
+		return 0, fmt.Errorf("%w: money value %q", ErrMalformedValue, s)
+	}
+
+	cents := int64(amount * scale)
+	if neg {
+		cents = -cents
+	}
+	return cents, nil
+}
@@ -0,0 +1,79 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+const moneyOID = 790
+
+func TestReadStructMoney(t *testing.T) {
+	type Cents int64
+	var dest struct {
+		M int64
+		C Cents
+		R *big.Rat
+	}
+
+	for _, tc := range []struct {
+		text  string
+		cents int64
+	}{
+		{"$1,234.56", 123456},
+		{"-$0.05", -5},
+		{"($7.10)", -710},
+		{"1.234,5 €", 123450},
+		{"￥1,234", 123400},
+	} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows("m", moneyOID, tc.text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest.M != tc.cents {
+			t.Errorf("value mismatch for %q: %v, want %v", tc.text, dest.M, tc.cents)
+		}
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("c", moneyOID, "$2.50"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.C != 250 {
+		t.Errorf("value mismatch for field C: %v", dest.C)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("r", moneyOID, "$2.50"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.R == nil || dest.R.Cmp(big.NewRat(5, 2)) != 0 {
+		t.Errorf("value mismatch for field R: %v", dest.R)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("m", moneyOID, "$"))
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("m", moneyOID, "$92,233,720,368,547,758.08"))
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+}
+
+func TestDecodeValueMoney(t *testing.T) {
+	var cents int64
+	err := pgxscan.DecodeValue(nil, moneyOID, 1, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x9c}, &cents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cents != -100 {
+		t.Errorf("value mismatch: %v", cents)
+	}
+
+	err = pgxscan.DecodeValue(nil, moneyOID, 1, []byte{1, 2}, &cents)
+	if !errors.Is(err, pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+)
+
+// Null holds a nullable value of type T.
+//
+// Valid is false for NULL, Value is the zero value then.
+// Other values are assigned to Value like to a field of type T,
+// so e.g. Null[time.Duration] holds a nullable interval.
+type Null[T any] struct {
+	Value T
+	Valid bool
+}
+
+// nullSetter is implemented by all Null types.
+type nullSetter interface {
+	setNull(v interface{}, fd *pgproto3.FieldDescription) error
+}
+
+func (n *Null[T]) setNull(v interface{}, fd *pgproto3.FieldDescription) error {
+	var nn Null[T]
+	if v != nil {
+		err := assignValue(reflect.ValueOf(&nn.Value).Elem(), v, fd)
+		if err != nil {
+			return err
+		}
+		nn.Valid = true
+	}
+
+	*n = nn
+	return nil
+}
+
+// NullAssigner lets nullable types, like option types or wrappers of null packages,
+// be filled w/o a converter and w/o pgxscan knowing their package.
+//
+// SetNull is called for NULL, SetValue w/ the value as returned by pgx for everything else.
+// An error returned by SetValue aborts the scan.
+type NullAssigner interface {
+	SetNull()
+	SetValue(v interface{}) error
+}
+
+// assignNullAssigner assigns v to na.
+func assignNullAssigner(na NullAssigner, v interface{}) error {
+	if v == nil {
+		na.SetNull()
+		return nil
+	}
+	return na.SetValue(v)
+}
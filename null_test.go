@@ -0,0 +1,117 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructNull(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("age"), DataTypeOID: pgtype.Int4OID},
+			{Name: []byte("ttl"), DataTypeOID: pgtype.IntervalOID},
+		},
+		vals: []interface{}{
+			"bob",
+			nil,
+			pgtype.Interval{Microseconds: 90e6, Status: pgtype.Present},
+		},
+	}
+
+	dest := struct {
+		Name pgxscan.Null[string]
+		Age  pgxscan.Null[int32]
+		TTL  pgxscan.Null[time.Duration]
+	}{Age: pgxscan.Null[int32]{Value: 7, Valid: true}}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.Name.Valid || dest.Name.Value != "bob" {
+		t.Errorf("value mismatch for field Name: %+v", dest.Name)
+	}
+	if dest.Age.Valid || dest.Age.Value != 0 {
+		t.Errorf("NULL not assigned to field Age: %+v", dest.Age)
+	}
+	if !dest.TTL.Valid || dest.TTL.Value != 90*time.Second {
+		t.Errorf("value mismatch for field TTL: %+v", dest.TTL)
+	}
+
+	var destB struct {
+		Name pgxscan.Null[int64]
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect invalid destination type: %v", err)
+	}
+}
+
+func TestReadNull(t *testing.T) {
+	var n pgxscan.Null[int64]
+	err := pgxscan.Read(&n, mkColumnRows("count", pgtype.Int8OID, int64(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || n.Value != 3 {
+		t.Errorf("value mismatch: %+v", n)
+	}
+}
+
+// option is a minimal option type as found in third-party packages.
+type option[T any] struct {
+	v  T
+	ok bool
+}
+
+func (o *option[T]) SetNull() {
+	*o = option[T]{}
+}
+
+func (o *option[T]) SetValue(v interface{}) error {
+	t, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", v)
+	}
+	*o = option[T]{v: t, ok: true}
+	return nil
+}
+
+func TestReadStructNullAssigner(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("age"), DataTypeOID: pgtype.Int4OID},
+		},
+		vals: []interface{}{"bob", nil},
+	}
+
+	dest := struct {
+		Name option[string]
+		Age  option[int32]
+	}{Age: option[int32]{v: 7, ok: true}}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.Name.ok || dest.Name.v != "bob" {
+		t.Errorf("value mismatch for field Name: %+v", dest.Name)
+	}
+	if dest.Age.ok || dest.Age.v != 0 {
+		t.Errorf("NULL not assigned to field Age: %+v", dest.Age)
+	}
+
+	var destB struct {
+		Name option[int64]
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err == nil {
+		t.Error("error of SetValue not returned")
+	}
+}
@@ -0,0 +1,220 @@
+package pgxscan
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgtype"
+)
+
+// RoundingPolicy decides how numerics w/ a fractional part are assigned to integer fields.
+type RoundingPolicy int
+
+const (
+	// RoundError rejects numerics w/ a fractional part, ErrOutOfRange is returned.
+	RoundError RoundingPolicy = iota
+	// RoundTruncate drops the fractional part, rounding towards zero.
+	RoundTruncate
+	// RoundHalfEven rounds to the nearest integer, ties to the even one.
+	RoundHalfEven
+)
+
+// NumericRounding is the policy for numerics assigned to integer fields.
+// Integral numerics are assigned w/ every policy, as long as they fit.
+var NumericRounding = RoundError
+
+// NumericFloats allows numerics in float64 and float32 fields and slices.
+// The values are rounded to the nearest float, so precision may be lost.
+var NumericFloats = false
+
+var (
+	bigIntType   = reflect.TypeOf((*big.Int)(nil))
+	bigRatType   = reflect.TypeOf((*big.Rat)(nil))
+	bigFloatType = reflect.TypeOf((*big.Float)(nil))
+)
+
+func isBigNumber(t reflect.Type) bool {
+	return t == bigIntType || t == bigRatType || t == bigFloatType
+}
+
+// assignBig assigns the numeric n to dest, which has to be one of *big.Int, *big.Rat or *big.Float.
+// The value is always exact, so a *big.Int only accepts numerics without fractional part.
+func assignBig(dest reflect.Value, n pgtype.Numeric) error {
+	if n.NaN || n.Int == nil {
+		// no representation for NaN in math/big
+		return ErrInvalidDestination
+	}
+
+	var res interface{}
+	switch dest.Type() {
+	case bigIntType:
+		i, ok := numericInt(n)
+		if !ok {
+			return ErrInvalidDestination
+		}
+		res = i
+	case bigRatType:
+		res = numericRat(n)
+	case bigFloatType:
+		res = new(big.Float).SetRat(numericRat(n))
+	default:
+		return ErrInvalidDestination
+	}
+
+	dest.Set(reflect.ValueOf(res))
+	return nil
+}
+
+// numericInt returns n as integer.
+// If n has a fractional part false is returned.
+func numericInt(n pgtype.Numeric) (*big.Int, bool) {
+	i := new(big.Int).Set(n.Int)
+	if n.Exp >= 0 {
+		return i.Mul(i, pow10(n.Exp)), true
+	}
+	_, rem := i.QuoRem(i, pow10(-n.Exp), new(big.Int))
+	return i, rem.Sign() == 0
+}
+
+// assignNumericInt assigns n to the integer field dest, rounded according to NumericRounding.
+func assignNumericInt(dest reflect.Value, n pgtype.Numeric) error {
+	if n.NaN || n.Int == nil {
+		return ErrInvalidDestination
+	}
+
+	i := new(big.Int).Set(n.Int)
+	if n.Exp >= 0 {
+		i.Mul(i, pow10(n.Exp))
+	} else {
+		d := pow10(-n.Exp)
+		var rem big.Int
+		i.QuoRem(i, d, &rem)
+		if rem.Sign() != 0 {
+			switch NumericRounding {
+			case RoundTruncate:
+			case RoundHalfEven:
+				// compare the remainder w/ half of the divisor
+				c := new(big.Int).Abs(&rem)
+				switch c.Lsh(c, 1).Cmp(d) {
+				case 1:
+					i.Add(i, big.NewInt(int64(rem.Sign())))
+				case 0:
+					if i.Bit(0) == 1 {
+						i.Add(i, big.NewInt(int64(rem.Sign())))
+					}
+				}
+			default:
+				return fmt.Errorf("%w: %s has a fractional part", ErrOutOfRange, numericString(n))
+			}
+		}
+	}
+
+	if isUint(dest) {
+		if !i.IsUint64() || dest.OverflowUint(i.Uint64()) {
+			return fmt.Errorf("%w: %s does not fit into %s", ErrOutOfRange, numericString(n), dest.Type())
+		}
+		dest.SetUint(i.Uint64())
+		return nil
+	}
+	if !i.IsInt64() || dest.OverflowInt(i.Int64()) {
+		return fmt.Errorf("%w: %s does not fit into %s", ErrOutOfRange, numericString(n), dest.Type())
+	}
+	dest.SetInt(i.Int64())
+	return nil
+}
+
+// numericFloat returns n as float64, rounded to the nearest float.
+func numericFloat(n pgtype.Numeric) (float64, error) {
+	var f float64
+	err := n.AssignTo(&f)
+	return f, err
+}
+
+// assignNumericSlice assigns the numeric array a to the slice dest.
+// Elements go to floats w/ NumericFloats or to types w/ a registered converter, like decimals.
+// The converter gets a pgtype.Numeric per element, nil for NULL.
+// NULL elements of float slices are handled according to NullElements.
+func assignNumericSlice(dest reflect.Value, a pgtype.NumericArray) error {
+	if dest.Kind() != reflect.Slice {
+		return ErrInvalidDestination
+	}
+	et := dest.Type().Elem()
+	conv := lookupConverter(et)
+	if conv == nil && !(NumericFloats && (et.Kind() == reflect.Float64 || et.Kind() == reflect.Float32)) {
+		return ErrInvalidDestination
+	}
+	if !isSimpleArray(a.Dimensions, len(a.Elements)) {
+		return ErrNotSimpleSlice
+	}
+
+	res := makeSlice(dest, len(a.Elements))
+	for i, n := range a.Elements {
+		if conv != nil {
+			var src interface{}
+			if n.Status == pgtype.Present {
+				src = n
+			}
+			if err := conv(res.Index(i), src); err != nil {
+				return err
+			}
+			continue
+		}
+		if n.Status == pgtype.Null {
+			if NullElements == NullElementsError {
+				return fmt.Errorf("%w: NULL element at index %d", ErrInvalidDestination, i)
+			}
+			// the element is zero already
+			continue
+		}
+		f, err := numericFloat(n)
+		if err != nil {
+			return err
+		}
+		res.Index(i).SetFloat(f)
+	}
+	dest.Set(res)
+	return nil
+}
+
+// isInteger checks for signed and unsigned integer kinds.
+func isInteger(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return isUint(v)
+}
+
+func numericRat(n pgtype.Numeric) *big.Rat {
+	if n.Exp >= 0 {
+		return new(big.Rat).SetInt(new(big.Int).Mul(n.Int, pow10(n.Exp)))
+	}
+	return new(big.Rat).SetFrac(n.Int, pow10(-n.Exp))
+}
+
+func pow10(exp int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
+
+// numericString formats n in plain decimal notation like Postgres, e.g. 12.50.
+func numericString(n pgtype.Numeric) string {
+	if n.NaN || n.Int == nil {
+		return "NaN"
+	}
+	if n.Exp >= 0 {
+		return new(big.Int).Mul(n.Int, pow10(n.Exp)).String()
+	}
+
+	digits := new(big.Int).Abs(n.Int).String()
+	frac := int(-n.Exp)
+	if len(digits) <= frac {
+		digits = strings.Repeat("0", frac-len(digits)+1) + digits
+	}
+	s := digits[:len(digits)-frac] + "." + digits[len(digits)-frac:]
+	if n.Int.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
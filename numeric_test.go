@@ -0,0 +1,224 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func mkNumeric(s string) pgtype.Numeric {
+	var n pgtype.Numeric
+	err := n.DecodeText(nil, []byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func TestReadStructBigNumbers(t *testing.T) {
+	var dest struct {
+		I *big.Int
+		R *big.Rat
+		F *big.Float
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("i"), DataTypeOID: pgtype.NumericOID},
+			{Name: []byte("r"), DataTypeOID: pgtype.NumericOID},
+			{Name: []byte("f"), DataTypeOID: pgtype.NumericOID},
+		},
+		vals: []interface{}{mkNumeric("12300"), mkNumeric("-1234.5678"), mkNumeric("0.25")},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.I.String() != "12300" {
+		t.Errorf("value mismatch for field I: %v", dest.I)
+	}
+	if dest.R.Cmp(big.NewRat(-12345678, 10000)) != 0 {
+		t.Errorf("value mismatch for field R: %v", dest.R)
+	}
+	if dest.F.Cmp(big.NewFloat(0.25)) != 0 {
+		t.Errorf("value mismatch for field F: %v", dest.F)
+	}
+
+	// trailing zeros after the decimal point still make an integer
+	rows.vals = []interface{}{mkNumeric("42.000"), nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.I.String() != "42" {
+		t.Errorf("value mismatch for field I: %v", dest.I)
+	}
+	if dest.R != nil || dest.F != nil {
+		t.Errorf("NULL not assigned as nil: %v %v", dest.R, dest.F)
+	}
+
+	// fractional part can't go into an integer
+	rows.vals = []interface{}{mkNumeric("42.5"), nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("fractional value not detected, error: %v", err)
+	}
+
+	rows.vals = []interface{}{nil, mkNumeric("NaN"), nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NaN not detected, error: %v", err)
+	}
+}
+
+func TestReadStructNumericRounding(t *testing.T) {
+	var dest struct {
+		I int64
+		U uint8
+	}
+
+	read := func(col, num string) error {
+		return pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.NumericOID, mkNumeric(num)))
+	}
+
+	err := read("i", "1200")
+	if err != nil || dest.I != 1200 {
+		t.Errorf("integral numeric not assigned: %d, error: %v", dest.I, err)
+	}
+	err = read("i", "2.5")
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("fractional value not detected, error: %v", err)
+	}
+	err = read("u", "256")
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+
+	defer func() { pgxscan.NumericRounding = pgxscan.RoundError }()
+	tests := []struct {
+		policy pgxscan.RoundingPolicy
+		num    string
+		want   int64
+	}{
+		{pgxscan.RoundTruncate, "2.9", 2},
+		{pgxscan.RoundTruncate, "-2.9", -2},
+		{pgxscan.RoundHalfEven, "2.5", 2},
+		{pgxscan.RoundHalfEven, "3.5", 4},
+		{pgxscan.RoundHalfEven, "-3.5", -4},
+		{pgxscan.RoundHalfEven, "2.51", 3},
+		{pgxscan.RoundHalfEven, "-2.49", -2},
+	}
+	for _, tt := range tests {
+		pgxscan.NumericRounding = tt.policy
+		err = read("i", tt.num)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest.I != tt.want {
+			t.Errorf("value mismatch for %s w/ policy %d: %d", tt.num, tt.policy, dest.I)
+		}
+	}
+}
+
+func TestReadStructNumericSlice(t *testing.T) {
+	arr := pgtype.NumericArray{
+		Elements:   []pgtype.Numeric{mkNumeric("1.25"), mkNumeric("-3")},
+		Dimensions: []pgtype.ArrayDimension{{Length: 2, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := mkColumnRows("amounts", pgtype.NumericArrayOID, arr)
+
+	var dest struct {
+		Amounts []float64
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("numerics assigned to floats w/o NumericFloats: %v", err)
+	}
+
+	pgxscan.NumericFloats = true
+	err = pgxscan.ReadStruct(&dest, rows)
+	pgxscan.NumericFloats = false
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Amounts, []float64{1.25, -3}) {
+		t.Errorf("value mismatch for field Amounts: %v", dest.Amounts)
+	}
+
+	// decimal types get the elements by their converter
+	type decimal struct {
+		Int *big.Int
+		Exp int32
+	}
+	typ := reflect.TypeOf(decimal{})
+	pgxscan.RegisterConverter(typ, func(dest reflect.Value, src interface{}) error {
+		n, ok := src.(pgtype.Numeric)
+		if !ok {
+			return pgxscan.ErrInvalidDestination
+		}
+		dest.Set(reflect.ValueOf(decimal{Int: n.Int, Exp: n.Exp}))
+		return nil
+	})
+	defer pgxscan.RegisterConverter(typ, nil)
+
+	var destB struct {
+		Amounts []decimal
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destB.Amounts) != 2 || destB.Amounts[0].Int.Int64() != 125 || destB.Amounts[0].Exp != -2 {
+		t.Errorf("value mismatch for field Amounts: %v", destB.Amounts)
+	}
+}
+
+func TestReadStructNumericSliceNull(t *testing.T) {
+	arr := pgtype.NumericArray{
+		Elements:   []pgtype.Numeric{mkNumeric("1.5"), {Status: pgtype.Null}},
+		Dimensions: []pgtype.ArrayDimension{{Length: 2, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := mkColumnRows("amounts", pgtype.NumericArrayOID, arr)
+
+	pgxscan.NumericFloats = true
+	defer func() { pgxscan.NumericFloats = false }()
+
+	var dest struct {
+		Amounts []float64
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Amounts, []float64{1.5, 0}) {
+		t.Errorf("value mismatch for field Amounts: %v", dest.Amounts)
+	}
+
+	var destA struct {
+		Amounts [2]float64
+	}
+	err = pgxscan.ReadStruct(&destA, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destA.Amounts != [2]float64{1.5, 0} {
+		t.Errorf("value mismatch for field Amounts: %v", destA.Amounts)
+	}
+
+	pgxscan.NullElements = pgxscan.NullElementsError
+	defer func() { pgxscan.NullElements = pgxscan.NullElementsZero }()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NULL element not rejected, error: %v", err)
+	}
+	err = pgxscan.ReadStruct(&destA, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NULL element not rejected, error: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package pgxscan
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strconv"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+// regTypes are the OIDs of the reg* alias types of oid, like regclass.
+// pgtype doesn't know them, in text format their values are names, in binary format OIDs.
+var regTypes = map[uint32]bool{
+	24:   true, // regproc
+	2202: true, // regprocedure
+	2203: true, // regoper
+	2204: true, // regoperator
+	2205: true, // regclass
+	2206: true, // regtype
+	3734: true, // regconfig
+	3769: true, // regdictionary
+	4089: true, // regnamespace
+	4096: true, // regrole
+}
+
+// assignRegValue assigns the value of a reg* column to dest.
+// string fields get the text representation, uint32 fields the OID.
+// Names can't be resolved to OIDs, only numeric text is assigned to uint32 fields.
+func assignRegValue(dest reflect.Value, v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		if dest.Kind() == reflect.Uint32 {
+			oid, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				return ErrInvalidDestination
+			}
+			dest.SetUint(oid)
+			return nil
+		}
+	case []byte:
+		if len(v) != 4 {
+			return ErrMalformedValue
+		}
+		return assignOID(dest, binary.BigEndian.Uint32(v))
+	}
+	return core.Assign(dest, reflect.ValueOf(v))
+}
+
+// assignOID assigns oid to uint32 fields and as decimal number to string fields.
+func assignOID(dest reflect.Value, oid uint32) error {
+	switch dest.Kind() {
+	case reflect.Uint32:
+		dest.SetUint(uint64(oid))
+	case reflect.String:
+		dest.SetString(strconv.FormatUint(uint64(oid), 10))
+	default:
+		return ErrInvalidDestination
+	}
+	return nil
+}
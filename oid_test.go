@@ -0,0 +1,52 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructOID(t *testing.T) {
+	const regclassOID = 2205
+	var dest struct {
+		OID      uint32
+		OIDText  string
+		Rel      string
+		RelOID   uint32
+		RelBin   uint32
+		RelBinTx string
+	}
+	for col, v := range map[string]struct {
+		oid uint32
+		v   interface{}
+	}{
+		"oid":      {pgtype.OIDOID, uint32(1259)},
+		"oidtext":  {pgtype.OIDOID, uint32(1259)},
+		"rel":      {regclassOID, "pg_class"},
+		"reloid":   {regclassOID, "16384"},
+		"relbin":   {regclassOID, []byte{0, 0, 4, 0xeb}},
+		"relbintx": {regclassOID, []byte{0, 0, 4, 0xeb}},
+	} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, v.oid, v.v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.OID != 1259 || dest.OIDText != "1259" {
+		t.Errorf("value mismatch for oid: %v %q", dest.OID, dest.OIDText)
+	}
+	if dest.Rel != "pg_class" || dest.RelOID != 16384 {
+		t.Errorf("value mismatch for regclass: %q %v", dest.Rel, dest.RelOID)
+	}
+	if dest.RelBin != 1259 || dest.RelBinTx != "1259" {
+		t.Errorf("value mismatch for binary regclass: %v %q", dest.RelBin, dest.RelBinTx)
+	}
+
+	// names can't be resolved
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("reloid", regclassOID, "pg_class"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("name in uint32 field not detected, error: %v", err)
+	}
+}
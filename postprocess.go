@@ -0,0 +1,51 @@
+package pgxscan
+
+import (
+	"reflect"
+	"sync"
+)
+
+// PostProcessorFnc is the signature for a function changing DB values before they are assigned.
+// field is the name of the struct field and v the value from the DB, nil for NULL.
+// The returned value is assigned instead of v.
+type PostProcessorFnc func(field string, v interface{}) (interface{}, error)
+
+var (
+	postProcessorsMu sync.RWMutex
+	postProcessors   = map[reflect.Type]PostProcessorFnc{}
+)
+
+// RegisterPostProcessor registers fnc for the struct type T.
+//
+// When ReadStruct fills a T, fnc is called for every matched field before the value is assigned.
+// This keeps normalization, like lower casing email addresses, in one place:
+//
+//	pgxscan.RegisterPostProcessor[User](func(field string, v interface{}) (interface{}, error) {
+//		if s, ok := v.(string); ok && field == "Email" {
+//			return strings.ToLower(s), nil
+//		}
+//		return v, nil
+//	})
+//
+// An error returned by fnc aborts the scan.
+// Registering a function again replaces the previous one, nil removes it.
+func RegisterPostProcessor[T any](fnc PostProcessorFnc) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	postProcessorsMu.Lock()
+	defer postProcessorsMu.Unlock()
+
+	if fnc == nil {
+		delete(postProcessors, t)
+		return
+	}
+	postProcessors[t] = fnc
+}
+
+// postProcessorFor returns the function registered for t or nil.
+func postProcessorFor(t reflect.Type) PostProcessorFnc {
+	postProcessorsMu.RLock()
+	defer postProcessorsMu.RUnlock()
+
+	return postProcessors[t]
+}
@@ -0,0 +1,81 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+type processedRecord struct {
+	Bigid  int64
+	String string
+}
+
+func TestRegisterPostProcessor(t *testing.T) {
+	var fields []string
+	pgxscan.RegisterPostProcessor[processedRecord](func(field string, v interface{}) (interface{}, error) {
+		fields = append(fields, field)
+		if s, ok := v.(string); ok {
+			return strings.ToUpper(s), nil
+		}
+		return v, nil
+	})
+	defer pgxscan.RegisterPostProcessor[processedRecord](nil)
+
+	var dest processedRecord
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.String != "XY" {
+		t.Errorf("value not processed: %q", dest.String)
+	}
+	if dest.Bigid != 703340046535533321 {
+		t.Error("value mismatch for field Bigid")
+	}
+	if len(fields) != 2 {
+		t.Errorf("unexpected calls: %v", fields)
+	}
+
+	// other types are not affected
+	var other struct {
+		String string
+	}
+	err = pgxscan.ReadStruct(&other, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.String != "xy" {
+		t.Errorf("unregistered type processed: %q", other.String)
+	}
+
+	// errors abort the scan
+	errBad := errors.New("bad value")
+	pgxscan.RegisterPostProcessor[processedRecord](func(field string, v interface{}) (interface{}, error) {
+		return nil, errBad
+	})
+	err = pgxscan.ReadStruct(&dest, mkTestRows())
+	var se *pgxscan.ScanError
+	if !errors.As(err, &se) || !errors.Is(err, errBad) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPostProcessorPanic(t *testing.T) {
+	pgxscan.RegisterPostProcessor[processedRecord](func(field string, v interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	defer pgxscan.RegisterPostProcessor[processedRecord](nil)
+
+	var dest processedRecord
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	var serr *pgxscan.ScanError
+	if !errors.As(err, &serr) {
+		t.Fatalf("panic not returned as ScanError, error: %v", err)
+	}
+	if serr.Field == "" || serr.Panic != "boom" || len(serr.Stack) == 0 {
+		t.Errorf("incomplete ScanError: %+v", serr)
+	}
+}
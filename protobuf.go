@@ -0,0 +1,170 @@
+package pgxscan
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// ProtobufMode adapts ReadStruct to structs generated by protoc-gen-go.
+//
+// Fields w/ a protobuf tag are matched by the proto name and the JSON name from the tag,
+// XXX_ fields of older generators are ignored.
+// Fields of the well-known wrapper types, like *wrapperspb.Int64Value, are nil for NULL
+// and get the value in their Value field otherwise.
+// Date and timestamp columns can be assigned to *timestamppb.Timestamp fields.
+var ProtobufMode = false
+
+// protoNames holds the names of a field from its protobuf tag.
+type protoNames struct {
+	name string
+	json string // only set if it differs from name
+}
+
+// protoCache holds the protobuf names of struct types, keyed by reflect.Type.
+var protoCache sync.Map
+
+// protoFields returns the protobuf names of the fields of the struct type t, keyed by field name.
+func protoFields(t reflect.Type) map[string]protoNames {
+	if names, ok := protoCache.Load(t); ok {
+		return names.(map[string]protoNames)
+	}
+
+	fields := core.Fields(t)
+
+	names := map[string]protoNames{}
+	for _, field := range fields {
+		f, ok := t.FieldByName(field)
+		if !ok {
+			continue
+		}
+		if tag, ok := f.Tag.Lookup("protobuf"); ok {
+			names[field] = parseProtoTag(tag)
+		}
+	}
+
+	protoCache.Store(t, names)
+	return names
+}
+
+// parseProtoTag returns the names from a tag like `protobuf:"varint,1,opt,name=user_id,json=userId,proto3"`.
+func parseProtoTag(tag string) protoNames {
+	var n protoNames
+	for _, opt := range strings.Split(tag, ",") {
+		k, v, _ := strings.Cut(opt, "=")
+		switch k {
+		case "name":
+			n.name = v
+		case "json":
+			n.json = v
+		}
+	}
+	return n
+}
+
+// structMatcher returns the name matcher used for the struct type t.
+func structMatcher(t reflect.Type) NameMatcherFnc {
+	m := nameMatcher()
+	if !ProtobufMode {
+		return m
+	}
+
+	names := protoFields(t)
+	return func(field, col string) bool {
+		if strings.HasPrefix(field, "XXX_") {
+			return false
+		}
+		n, ok := names[field]
+		if !ok {
+			return m(field, col)
+		}
+		return m(n.name, col) || (n.json != "" && m(n.json, col))
+	}
+}
+
+// isProtoWrapper checks for a pointer to a well-known wrapper type, like *wrapperspb.StringValue.
+// These are named *Value and have a single exported field Value.
+func isProtoWrapper(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	t = t.Elem()
+	if !strings.HasSuffix(t.Name(), "Value") {
+		return false
+	}
+
+	var value bool
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Name != "Value" || f.Tag.Get("protobuf") == "" {
+			return false
+		}
+		value = true
+	}
+	return value
+}
+
+// assignProtoWrapper assigns v to the Value field of a newly allocated wrapper, NULL is nil.
+func assignProtoWrapper(dest reflect.Value, v interface{}, fd *pgproto3.FieldDescription) error {
+	if v == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	nv := reflect.New(dest.Type().Elem())
+	err := assignValue(nv.Elem().FieldByName("Value"), v, fd)
+	if err != nil {
+		return err
+	}
+	dest.Set(nv)
+	return nil
+}
+
+// isProtoTimestamp checks for a pointer to the well-known type timestamppb.Timestamp.
+// It is matched by shape: a struct named Timestamp w/ the exported fields Seconds and Nanos.
+func isProtoTimestamp(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct || t.Elem().Name() != "Timestamp" {
+		return false
+	}
+	t = t.Elem()
+	seconds, ok := t.FieldByName("Seconds")
+	if !ok || seconds.Type.Kind() != reflect.Int64 || seconds.Tag.Get("protobuf") == "" {
+		return false
+	}
+	nanos, ok := t.FieldByName("Nanos")
+	return ok && nanos.Type.Kind() == reflect.Int32 && nanos.Tag.Get("protobuf") != ""
+}
+
+// assignProtoTimestamp assigns the time.Time v to a newly allocated timestamp, NULL is nil.
+func assignProtoTimestamp(dest reflect.Value, v interface{}) error {
+	if v == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	if im, ok := v.(pgtype.InfinityModifier); ok {
+		// converted or rejected like for time.Time fields
+		t := reflect.New(timeType).Elem()
+		if _, err := assignInfinity(t, im); err != nil {
+			return err
+		}
+		v = t.Interface()
+	}
+	t, ok := v.(time.Time)
+	if !ok {
+		return ErrInvalidDestination
+	}
+
+	nv := reflect.New(dest.Type().Elem())
+	nv.Elem().FieldByName("Seconds").SetInt(t.Unix())
+	nv.Elem().FieldByName("Nanos").SetInt(int64(t.Nanosecond()))
+	dest.Set(nv)
+	return nil
+}
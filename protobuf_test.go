@@ -0,0 +1,132 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// pbStringValue has the shape of wrapperspb.StringValue.
+type pbStringValue struct {
+	state     struct{}
+	sizeCache int32
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+// pbUser has the shape of a struct generated by protoc-gen-go.
+type pbUser struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	UserId   int64          `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname *pbStringValue `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Email    *pbStringValue `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+
+	XXX_unrecognized []byte `json:"-"`
+}
+
+func TestReadStructProtobuf(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("user_id"), DataTypeOID: pgtype.Int8OID},
+			{Name: []byte("nickname"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("email"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("xxx_unrecognized"), DataTypeOID: pgtype.ByteaOID},
+		},
+		vals: []interface{}{int64(42), "bob", nil, []byte{1}},
+	}
+
+	pgxscan.ProtobufMode = true
+	defer func() { pgxscan.ProtobufMode = false }()
+
+	dest := pbUser{Email: &pbStringValue{Value: "x"}}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.UserId != 42 {
+		t.Errorf("value mismatch for field UserId: %d", dest.UserId)
+	}
+	if dest.Nickname == nil || dest.Nickname.Value != "bob" {
+		t.Errorf("value mismatch for field Nickname: %v", dest.Nickname)
+	}
+	if dest.Email != nil {
+		t.Errorf("NULL not assigned as nil to field Email: %v", dest.Email)
+	}
+	if dest.XXX_unrecognized != nil {
+		t.Errorf("field XXX_unrecognized was filled: %v", dest.XXX_unrecognized)
+	}
+
+	// the JSON name matches too
+	var destB pbUser
+	err = pgxscan.ReadStruct(&destB, mkColumnRows("userId", pgtype.Int8OID, int64(7)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destB.UserId != 7 {
+		t.Errorf("value mismatch for field UserId: %d", destB.UserId)
+	}
+}
+
+// Timestamp has the shape of timestamppb.Timestamp, which is matched by name.
+type Timestamp struct {
+	state     struct{}
+	sizeCache int32
+
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos   int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func TestReadStructProtobufTimestamp(t *testing.T) {
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC)
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("created_at"), DataTypeOID: pgtype.TimestamptzOID},
+			{Name: []byte("deleted_at"), DataTypeOID: pgtype.TimestamptzOID},
+		},
+		vals: []interface{}{ts, nil},
+	}
+
+	var dest struct {
+		CreatedAt *Timestamp `protobuf:"bytes,1,opt,name=created_at,json=createdAt,proto3"`
+		DeletedAt *Timestamp `protobuf:"bytes,2,opt,name=deleted_at,json=deletedAt,proto3"`
+	}
+
+	pgxscan.ProtobufMode = true
+	defer func() { pgxscan.ProtobufMode = false }()
+
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.CreatedAt == nil || dest.CreatedAt.Seconds != ts.Unix() || dest.CreatedAt.Nanos != 8000 {
+		t.Errorf("value mismatch for field CreatedAt: %+v", dest.CreatedAt)
+	}
+	if dest.DeletedAt != nil {
+		t.Errorf("NULL not assigned as nil to field DeletedAt: %+v", dest.DeletedAt)
+	}
+
+	// infinity is rejected like for time.Time fields
+	rows.vals = []interface{}{pgtype.Infinity, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("infinity not rejected w/ ErrOutOfRange, error: %v", err)
+	}
+
+	pgxscan.Infinity = pgxscan.InfinitySentinel
+	defer func() { pgxscan.Infinity = pgxscan.InfinityError }()
+	rows.vals = []interface{}{pgtype.NegativeInfinity, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.CreatedAt == nil || dest.CreatedAt.Seconds != pgxscan.MinTime.Unix() {
+		t.Errorf("-infinity not assigned as MinTime: %+v", dest.CreatedAt)
+	}
+}
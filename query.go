@@ -0,0 +1,77 @@
+package pgxscan
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	// ErrNoRows is returned by GetRow if the query returned no record.
+	ErrNoRows = errors.New("no rows in result set")
+	// ErrTooManyRows is returned by GetRow if the query returned more than one record.
+	ErrTooManyRows = errors.New("more than one row in result set")
+)
+
+// Querier runs queries, it is implemented by *pgx.Conn, *pgxpool.Pool and pgx.Tx.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// WithPoolerCompatibility returns a Querier running the queries of q w/ the simple protocol.
+//
+// pgx prepares statements by default, which fails behind poolers in transaction mode,
+// like PgBouncer, as a prepared statement is bound to the server connection it was created on.
+// The simple protocol needs no prepared statements and no other session state.
+// The result can be passed to all helpers taking a Querier, GetRow and LoadEnums:
+//
+//	err := pgxscan.GetRow(ctx, pgxscan.WithPoolerCompatibility(pool), &user, sql, id)
+//
+// Arguments are sent as text then, see pgx.QuerySimpleProtocol.
+func WithPoolerCompatibility(q Querier) Querier {
+	return poolerQuerier{q: q}
+}
+
+// poolerQuerier runs all queries w/ the simple protocol.
+type poolerQuerier struct {
+	q Querier
+}
+
+func (pq poolerQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	args = append([]interface{}{pgx.QuerySimpleProtocol(true)}, args...)
+	return pq.q.Query(ctx, sql, args...)
+}
+
+// GetRow runs the query sql and reads the only resulting record into dest.
+//
+// It is the replacement for QueryRow(...).Scan(...) call sites.
+// ErrNoRows is returned if there is no record, ErrTooManyRows if there is more than one.
+// The rows are closed in any case, even if ReadStruct panics.
+func GetRow(ctx context.Context, q Querier, dest interface{}, sql string, args ...interface{}) error {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNoRows
+		}
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return ErrNoRows
+	}
+
+	err = ReadStruct(dest, rows)
+	if err != nil {
+		return err
+	}
+
+	if rows.Next() {
+		return ErrTooManyRows
+	}
+	return Finish(rows)
+}
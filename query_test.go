@@ -0,0 +1,91 @@
+package pgxscan_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgx/v4"
+)
+
+// fakeQuerier returns rows for every query.
+type fakeQuerier struct {
+	rows *fakeRows
+	err  error
+	args []interface{} // of the last query
+}
+
+func (q *fakeQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q.args = args
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.rows, nil
+}
+
+func TestGetRow(t *testing.T) {
+	ctx := context.Background()
+	var dest struct {
+		Bigid int64
+	}
+
+	q := &fakeQuerier{rows: &fakeRows{testRows: mkTestRows(), n: 1}}
+	err := pgxscan.GetRow(ctx, q, &dest, "select")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != 703340046535533321 {
+		t.Error("value mismatch for field Bigid")
+	}
+	if !q.rows.closed {
+		t.Error("rows not closed")
+	}
+
+	q = &fakeQuerier{rows: &fakeRows{testRows: mkTestRows()}}
+	err = pgxscan.GetRow(ctx, q, &dest, "select")
+	if err != pgxscan.ErrNoRows {
+		t.Errorf("missing row not detected, error: %v", err)
+	}
+
+	q = &fakeQuerier{err: pgx.ErrNoRows}
+	err = pgxscan.GetRow(ctx, q, &dest, "select")
+	if err != pgxscan.ErrNoRows {
+		t.Errorf("pgx.ErrNoRows not mapped, error: %v", err)
+	}
+
+	q = &fakeQuerier{rows: &fakeRows{testRows: mkTestRows(), n: 2}}
+	err = pgxscan.GetRow(ctx, q, &dest, "select")
+	if err != pgxscan.ErrTooManyRows {
+		t.Errorf("additional row not detected, error: %v", err)
+	}
+	if !q.rows.closed {
+		t.Error("rows not closed")
+	}
+
+	errLost := errors.New("connection lost")
+	q = &fakeQuerier{rows: &fakeRows{testRows: mkTestRows(), n: 1}}
+	q.rows.errSet = errLost
+	err = pgxscan.GetRow(ctx, q, &dest, "select")
+	if !errors.Is(err, errLost) {
+		t.Errorf("rows error not returned: %v", err)
+	}
+}
+
+func TestWithPoolerCompatibility(t *testing.T) {
+	var dest struct {
+		Bigid int64
+	}
+
+	q := &fakeQuerier{rows: &fakeRows{testRows: mkTestRows(), n: 1}}
+	err := pgxscan.GetRow(context.Background(), pgxscan.WithPoolerCompatibility(q), &dest, "select $1", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.args) != 2 || q.args[0] != pgx.QuerySimpleProtocol(true) || q.args[1] != 42 {
+		t.Errorf("query not run w/ the simple protocol, args: %v", q.args)
+	}
+	if dest.Bigid != 703340046535533321 {
+		t.Error("value mismatch for field Bigid")
+	}
+}
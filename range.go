@@ -0,0 +1,78 @@
+package pgxscan
+
+import (
+	"reflect"
+
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+// Range holds a Postgres range w/ bounds of type T.
+//
+// Lower and Upper are nil if the range is unbounded on that side.
+// An empty range has Empty set and no bounds.
+// The bounds are assigned like struct fields, so e.g. Range[*big.Rat] holds a numrange w/o loss.
+type Range[T any] struct {
+	Lower          *T
+	Upper          *T
+	LowerInclusive bool
+	UpperInclusive bool
+	Empty          bool
+}
+
+// rangeSetter is implemented by all Range types.
+type rangeSetter interface {
+	setRange(lower, upper interface{}, lowerType, upperType pgtype.BoundType, fd *pgproto3.FieldDescription) error
+}
+
+func (r *Range[T]) setRange(lower, upper interface{}, lowerType, upperType pgtype.BoundType, fd *pgproto3.FieldDescription) error {
+	var nr Range[T]
+	if lowerType == pgtype.Empty {
+		nr.Empty = true
+		*r = nr
+		return nil
+	}
+
+	var err error
+	nr.Lower, err = rangeBound[T](lower, lowerType, fd)
+	if err != nil {
+		return err
+	}
+	nr.Upper, err = rangeBound[T](upper, upperType, fd)
+	if err != nil {
+		return err
+	}
+	nr.LowerInclusive = lowerType == pgtype.Inclusive
+	nr.UpperInclusive = upperType == pgtype.Inclusive
+
+	*r = nr
+	return nil
+}
+
+// rangeBound converts the bound v, nil is returned for unbounded sides.
+func rangeBound[T any](v interface{}, bt pgtype.BoundType, fd *pgproto3.FieldDescription) (*T, error) {
+	if bt == pgtype.Unbounded {
+		return nil, nil
+	}
+
+	b := new(T)
+	bv := reflect.ValueOf(b).Elem()
+	efd := elementFD(fd)
+	err := assignValue(bv, normalizeValue(bv, v, efd.DataTypeOID), efd)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// assignRange assigns a range to dest, which has to be a Range.
+func assignRange(dest reflect.Value, lower, upper pgtype.Value, lowerType, upperType pgtype.BoundType, fd *pgproto3.FieldDescription) error {
+	if !dest.CanAddr() {
+		return ErrInvalidDestination
+	}
+	rs, ok := dest.Addr().Interface().(rangeSetter)
+	if !ok {
+		return ErrInvalidDestination
+	}
+	return rs.setRange(lower.Get(), upper.Get(), lowerType, upperType, fd)
+}
@@ -0,0 +1,112 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructRange(t *testing.T) {
+	var r4 pgtype.Int4range
+	if err := r4.DecodeText(nil, []byte("[1,10)")); err != nil {
+		t.Fatal(err)
+	}
+	var r8 pgtype.Int8range
+	if err := r8.DecodeText(nil, []byte("(,42]")); err != nil {
+		t.Fatal(err)
+	}
+	var rn pgtype.Numrange
+	if err := rn.DecodeText(nil, []byte("[1.5,2.25]")); err != nil {
+		t.Fatal(err)
+	}
+	var re pgtype.Int4range
+	if err := re.DecodeText(nil, []byte("empty")); err != nil {
+		t.Fatal(err)
+	}
+
+	var dest struct {
+		A pgxscan.Range[int32]
+		B *pgxscan.Range[int64]
+		C pgxscan.Range[*big.Rat]
+		D pgxscan.Range[int32]
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("a"), DataTypeOID: pgtype.Int4rangeOID},
+			{Name: []byte("b"), DataTypeOID: pgtype.Int8rangeOID},
+			{Name: []byte("c"), DataTypeOID: pgtype.NumrangeOID},
+			{Name: []byte("d"), DataTypeOID: pgtype.Int4rangeOID},
+		},
+		vals: []interface{}{r4.Get(), r8.Get(), rn.Get(), re.Get()},
+	}
+
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dest.A.Lower == nil || *dest.A.Lower != 1 || !dest.A.LowerInclusive ||
+		dest.A.Upper == nil || *dest.A.Upper != 10 || dest.A.UpperInclusive || dest.A.Empty {
+		t.Errorf("value mismatch for field A: %+v", dest.A)
+	}
+	if dest.B == nil || dest.B.Lower != nil || dest.B.Upper == nil || *dest.B.Upper != 42 || !dest.B.UpperInclusive {
+		t.Errorf("value mismatch for field B: %+v", dest.B)
+	}
+	if dest.C.Lower == nil || (*dest.C.Lower).Cmp(big.NewRat(3, 2)) != 0 ||
+		dest.C.Upper == nil || (*dest.C.Upper).Cmp(big.NewRat(9, 4)) != 0 {
+		t.Errorf("value mismatch for field C: %+v", dest.C)
+	}
+	if !dest.D.Empty || dest.D.Lower != nil || dest.D.Upper != nil {
+		t.Errorf("value mismatch for field D: %+v", dest.D)
+	}
+
+	// bounds have to fit
+	var bad struct {
+		A pgxscan.Range[string]
+	}
+	err = pgxscan.ReadStruct(&bad, mkColumnRows("a", pgtype.Int4rangeOID, r4.Get()))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid bound type not detected, error: %v", err)
+	}
+}
+
+func TestReadStructTimeRange(t *testing.T) {
+	var tr pgtype.Tstzrange
+	if err := tr.DecodeText(nil, []byte(`["2021-03-01 10:00:00+00","2021-03-01 12:00:00+00")`)); err != nil {
+		t.Fatal(err)
+	}
+	var dr pgtype.Daterange
+	if err := dr.DecodeText(nil, []byte("[2021-03-01,)")); err != nil {
+		t.Fatal(err)
+	}
+
+	var dest struct {
+		Booked pgxscan.Range[time.Time]
+		Valid  pgxscan.Range[time.Time]
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("booked"), DataTypeOID: pgtype.TstzrangeOID},
+			{Name: []byte("valid"), DataTypeOID: pgtype.DaterangeOID},
+		},
+		vals: []interface{}{tr.Get(), dr.Get()},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	if dest.Booked.Lower == nil || !dest.Booked.Lower.Equal(from) || !dest.Booked.LowerInclusive ||
+		dest.Booked.Upper == nil || !dest.Booked.Upper.Equal(from.Add(2*time.Hour)) || dest.Booked.UpperInclusive {
+		t.Errorf("value mismatch for field Booked: %+v", dest.Booked)
+	}
+	if dest.Valid.Lower == nil || !dest.Valid.Lower.Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)) || dest.Valid.Upper != nil {
+		t.Errorf("value mismatch for field Valid: %+v", dest.Valid)
+	}
+}
@@ -0,0 +1,104 @@
+package pgxscan
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/guidog/pgxscan/core"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrNotSingleColumn is returned by Read if a non-struct destination is used for a result w/ more than one column.
+var ErrNotSingleColumn = errors.New("result has not exactly one column")
+
+// Read scans the current record in rows into dest, which has to be a pointer.
+//
+// Structs are filled by ReadStruct. Every other destination, e.g. *int64, *[]string
+// or *map[string]string, receives the value of the only column of the result.
+// Structs holding a single value, like time.Time, Interval, Range or types implementing
+// sql.Scanner, are treated like scalars, as are structs receiving a single JSON column
+// which matches none of their fields.
+func Read(dest interface{}, rows PgxRows) error {
+	return read(dest, rows, nil)
+}
+
+// read implements Read, st tracks the size of the result for the collectors.
+func read(dest interface{}, rows PgxRows, st *sizeTracker) error {
+	if dest == nil {
+		return ErrDestNil
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr {
+		return ErrNotPointer
+	}
+	if dv.IsNil() {
+		return ErrDestNil
+	}
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	fds := rows.FieldDescriptions()
+	elem := dv.Elem()
+	if elem.Kind() == reflect.Struct && !isValueStruct(elem) &&
+		!(len(fds) == 1 && isJSON(fds[0].DataTypeOID) && !matchesColumn(elem, fds[0])) {
+		return readStruct(dest, rows, st)
+	}
+
+	if len(fds) != 1 {
+		return ErrNotSingleColumn
+	}
+	vals, err := rows.Values()
+	if err != nil {
+		return err
+	}
+	if err := checkSize(vals, st); err != nil {
+		return err
+	}
+	return assignField(elem, vals[0], &fds[0], "")
+}
+
+// ScanAll reads all remaining records in rows w/ Read and closes rows.
+// T can be a struct, filled like w/ ReadStruct, or any type for a single column result,
+// e.g. ScanAll[User](rows) or ScanAll[int64](rows).
+func ScanAll[T any](rows pgx.Rows) ([]T, error) {
+	defer rows.Close()
+
+	var res []T
+	var st sizeTracker
+	for rows.Next() {
+		var v T
+		err := read(&v, rows, &st)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, finishCollect(rows)
+}
+
+// matchesColumn checks if a field of the struct v is matched to the column fd, like by ReadStruct.
+func matchesColumn(v reflect.Value, fd pgproto3.FieldDescription) bool {
+	fieldNames := matchColumns(core.Fields(v.Type()), []pgproto3.FieldDescription{fd}, structMatcher(v.Type()), columnMapping(v.Type()))
+	return fieldNames[0] != ""
+}
+
+// isValueStruct checks if the struct v is assigned as a single value.
+func isValueStruct(v reflect.Value) bool {
+	t := v.Type()
+	switch t {
+	case timeType, dateType, timeOfDayType, timeTZType, intervalType, pointType, boxType, circleType, lineType, pathType, bitsetType:
+		return true
+	}
+	if lookupConverter(t) != nil {
+		return true
+	}
+	switch v.Addr().Interface().(type) {
+	case pgtype.Value, sql.Scanner, rangeSetter, nullSetter, NullAssigner:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package pgxscan_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestRead(t *testing.T) {
+	var id int64
+	err := pgxscan.Read(&id, mkColumnRows("bigid", pgtype.Int8OID, int64(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("value mismatch: %v", id)
+	}
+
+	var ta pgtype.TextArray
+	if err := ta.Set([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	var s []string
+	err = pgxscan.Read(&s, mkColumnRows("a", pgtype.TextArrayOID, ta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, []string{"a", "b"}) {
+		t.Errorf("value mismatch: %v", s)
+	}
+
+	var m map[string]string
+	err = pgxscan.Read(&m, mkColumnRows("h", 16400, mkHstore(`"k"=>"v"`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["k"] != "v" {
+		t.Errorf("value mismatch: %v", m)
+	}
+
+	// value structs are not filled by name
+	var ns sql.NullString
+	err = pgxscan.Read(&ns, mkColumnRows("string", pgtype.TextOID, "xy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ns.Valid || ns.String != "xy" {
+		t.Errorf("value mismatch: %v", ns)
+	}
+
+	// structs are filled by ReadStruct
+	var dest struct {
+		Bigid int64
+	}
+	err = pgxscan.Read(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != 703340046535533321 {
+		t.Error("value mismatch for field Bigid")
+	}
+
+	err = pgxscan.Read(&id, mkTestRows())
+	if err != pgxscan.ErrNotSingleColumn {
+		t.Errorf("multiple columns not detected, error: %v", err)
+	}
+	err = pgxscan.Read(id, mkTestRows())
+	if err != pgxscan.ErrNotPointer {
+		t.Errorf("non pointer not detected, error: %v", err)
+	}
+}
+
+func TestScanAll(t *testing.T) {
+	type pair struct {
+		ID   int64
+		Name string
+	}
+
+	rows := &fakeRows{testRows: mkPairRows(), n: 2}
+	pairs, err := pgxscan.ScanAll[pair](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pairs, []pair{{ID: 7, Name: "x"}, {ID: 7, Name: "x"}}) {
+		t.Errorf("unexpected structs: %v", pairs)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+
+	counts, err := pgxscan.ScanAll[int64](&fakeRows{testRows: mkColumnRows("count", pgtype.Int8OID, int64(3)), n: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counts, []int64{3}) {
+		t.Errorf("unexpected values: %v", counts)
+	}
+
+	// single values need a single column
+	_, err = pgxscan.ScanAll[int64](&fakeRows{testRows: mkPairRows(), n: 1})
+	if err == nil {
+		t.Error("failed to detect result w/ two columns")
+	}
+}
+
+func TestReadJSONColumn(t *testing.T) {
+	doc := map[string]interface{}{"a": float64(1)}
+
+	// a field matching the column receives the document
+	type record struct {
+		Doc json.RawMessage
+	}
+	var want, got record
+	if err := pgxscan.ReadStruct(&want, mkColumnRows("doc", pgtype.JSONBOID, doc)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pgxscan.Read(&got, mkColumnRows("doc", pgtype.JSONBOID, doc)); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Doc) != `{"a":1}` || !reflect.DeepEqual(got, want) {
+		t.Errorf("Read differs from ReadStruct: %s, %s", got.Doc, want.Doc)
+	}
+
+	recs, err := pgxscan.ScanAll[record](&fakeRows{testRows: mkColumnRows("doc", pgtype.JSONBOID, doc), n: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || string(recs[0].Doc) != `{"a":1}` {
+		t.Errorf("unexpected structs: %v", recs)
+	}
+
+	// w/o a matching field the document is unmarshaled into the struct
+	var whole struct {
+		A int `json:"a"`
+	}
+	if err := pgxscan.Read(&whole, mkColumnRows("doc", pgtype.JSONBOID, doc)); err != nil {
+		t.Fatal(err)
+	}
+	if whole.A != 1 {
+		t.Errorf("value mismatch: %+v", whole)
+	}
+}
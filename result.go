@@ -0,0 +1,135 @@
+package pgxscan
+
+import (
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+)
+
+// CapturedColumn describes a column of a CapturedResult.
+type CapturedColumn struct {
+	Name   string
+	OID    uint32
+	Format int16
+}
+
+// CapturedResult holds a complete result in wire format.
+//
+// It consists of plain data, so it can be encoded w/ encoding/json or encoding/gob
+// and be stored in a cache. Replay returns rows to read it w/o a connection.
+type CapturedResult struct {
+	Columns []CapturedColumn
+	// Records holds the raw values of every record, nil is NULL.
+	Records [][][]byte
+}
+
+// CaptureResult reads all records of rows and closes them.
+func CaptureResult(rows pgx.Rows) (*CapturedResult, error) {
+	defer rows.Close()
+
+	cr := &CapturedResult{}
+	for _, fd := range rows.FieldDescriptions() {
+		cr.Columns = append(cr.Columns, CapturedColumn{
+			Name:   string(fd.Name),
+			OID:    fd.DataTypeOID,
+			Format: fd.Format,
+		})
+	}
+
+	for rows.Next() {
+		raw := rows.RawValues()
+		rec := make([][]byte, len(raw))
+		for i, r := range raw {
+			if r != nil {
+				// pgx reuses the buffer
+				rec[i] = append([]byte{}, r...)
+			}
+		}
+		cr.Records = append(cr.Records, rec)
+	}
+
+	if err := Finish(rows); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Replay returns rows reading the captured records.
+// The values are decoded w/ the types known to ci, if nil the defaults of pgtype are used.
+func (cr *CapturedResult) Replay(ci *pgtype.ConnInfo) *ReplayRows {
+	if ci == nil {
+		ci = pgtype.NewConnInfo()
+	}
+	fds := make([]pgproto3.FieldDescription, len(cr.Columns))
+	for i, c := range cr.Columns {
+		fds[i] = pgproto3.FieldDescription{Name: []byte(c.Name), DataTypeOID: c.OID, Format: c.Format}
+	}
+	return &ReplayRows{cr: cr, ci: ci, fds: fds, pos: -1}
+}
+
+// ReplayRows reads the records of a CapturedResult.
+// It implements PgxRows, Next advances to the next record like pgx.Rows.Next.
+type ReplayRows struct {
+	cr  *CapturedResult
+	ci  *pgtype.ConnInfo
+	fds []pgproto3.FieldDescription
+	pos int
+	err error
+}
+
+// Next advances to the next record, it returns false after the last one.
+func (rr *ReplayRows) Next() bool {
+	if rr.err != nil || rr.pos+1 >= len(rr.cr.Records) {
+		rr.pos = len(rr.cr.Records)
+		return false
+	}
+	rr.pos++
+	return true
+}
+
+// FieldDescriptions returns the descriptions of the captured columns.
+func (rr *ReplayRows) FieldDescriptions() []pgproto3.FieldDescription {
+	return rr.fds
+}
+
+// Values decodes the values of the current record.
+// Malformed values return an error wrapping ErrMalformedValue.
+func (rr *ReplayRows) Values() ([]interface{}, error) {
+	if rr.pos < 0 || rr.pos >= len(rr.cr.Records) {
+		return nil, ErrNoRows
+	}
+	rec := rr.cr.Records[rr.pos]
+	if len(rec) != len(rr.fds) {
+		rr.err = ErrColumnCount
+		return nil, rr.err
+	}
+
+	vals := make([]interface{}, len(rec))
+	for i, raw := range rec {
+		v, err := decodeRaw(rr.ci, rr.fds[i].DataTypeOID, rr.fds[i].Format, raw)
+		if err != nil {
+			rr.err = err
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// ConnInfo returns the types the values are decoded w/.
+func (rr *ReplayRows) ConnInfo() *pgtype.ConnInfo {
+	return rr.ci
+}
+
+// RawValues returns the raw values of the current record.
+func (rr *ReplayRows) RawValues() [][]byte {
+	if rr.pos < 0 || rr.pos >= len(rr.cr.Records) {
+		return nil
+	}
+	return rr.cr.Records[rr.pos]
+}
+
+// Err returns the first error decoding a record.
+func (rr *ReplayRows) Err() error {
+	return rr.err
+}
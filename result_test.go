@@ -0,0 +1,61 @@
+package pgxscan_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+func TestCaptureResult(t *testing.T) {
+	rows := &fakeRows{testRows: mkPairRows(), n: 2}
+	cr, err := pgxscan.CaptureResult(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+
+	// cache round trip
+	b, err := json.Marshal(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cached pgxscan.CapturedResult
+	err = json.Unmarshal(b, &cached)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := cached.Replay(nil)
+	n := 0
+	for rr.Next() {
+		var dest struct {
+			ID   int64
+			Name string
+		}
+		err = pgxscan.ReadStruct(&dest, rr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest.ID != 7 || dest.Name != "x" {
+			t.Errorf("value mismatch: %+v", dest)
+		}
+		n++
+	}
+	if n != 2 || rr.Err() != nil {
+		t.Errorf("unexpected result: %d records, error: %v", n, rr.Err())
+	}
+
+	// malformed values are reported
+	cached.Records[0][0] = []byte("x")
+	rr = cached.Replay(pgtype.NewConnInfo())
+	rr.Next()
+	_, err = rr.Values()
+	if !errors.Is(err, pgxscan.ErrMalformedValue) || !errors.Is(rr.Err(), pgxscan.ErrMalformedValue) {
+		t.Errorf("malformed value not detected, error: %v", err)
+	}
+}
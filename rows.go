@@ -0,0 +1,188 @@
+package pgxscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	// ErrRowsNotDrained is reported when rows are closed before all records were read.
+	ErrRowsNotDrained = errors.New("rows closed before all records were read")
+	// ErrRowsNotClosed is reported when rows are abandoned w/o being drained or closed.
+	ErrRowsNotClosed = errors.New("rows abandoned w/o close")
+	// ErrPartialResult is returned by the collectors w/ PartialResults if a timeout ended the result early.
+	ErrPartialResult = errors.New("partial result")
+)
+
+// PartialResults makes the collectors ReadStructs, ScanAll, ScanTuples2 and ScanTuples3
+// keep the records read before the deadline of the query context passed.
+// They are returned w/ an error matching ErrPartialResult and the timeout, like context.DeadlineExceeded.
+// W/o PartialResults ReadStructs leaves its destination untouched on timeouts.
+// Meant for dashboards preferring partial data over errors.
+var PartialResults = false
+
+// pgx.Rows, and so every mock implementing it, must be usable w/ ReadStruct
+var _ PgxRows = pgx.Rows(nil)
+
+// RowsHookFnc is the signature for a function receiving reports about misused rows.
+type RowsHookFnc func(err error)
+
+// TrackedRows wraps pgx.Rows and tracks if the rows are drained and closed.
+//
+// Rows which are neither drained nor closed keep their connection busy,
+// which can exhaust a connection pool.
+type TrackedRows struct {
+	pgx.Rows
+	hook    RowsHookFnc
+	drained bool
+	closed  bool
+}
+
+// TrackRows returns rows wrapped in a TrackedRows.
+//
+// hook is called with ErrRowsNotDrained if Close is called before Next returned false.
+// If the wrapper is garbage collected w/o being drained or closed, hook is called with ErrRowsNotClosed.
+// That report happens on the finalizer goroutine at an unspecified time.
+// hook may be nil, Check can be used instead.
+func TrackRows(rows pgx.Rows, hook RowsHookFnc) *TrackedRows {
+	tr := &TrackedRows{
+		Rows: rows,
+		hook: hook,
+	}
+	runtime.SetFinalizer(tr, func(tr *TrackedRows) {
+		if err := tr.Check(); err == ErrRowsNotClosed {
+			tr.report(err)
+		}
+	})
+	return tr
+}
+
+// Next advances to the next record, see pgx.Rows.
+func (tr *TrackedRows) Next() bool {
+	ok := tr.Rows.Next()
+	if !ok {
+		// pgx closes the rows after the last record and on errors,
+		// the records after an error were never read
+		tr.drained = tr.Rows.Err() == nil
+		tr.closed = true
+	}
+	return ok
+}
+
+// Close closes the rows, see pgx.Rows.
+// If not all records were read, the hook is called with ErrRowsNotDrained.
+func (tr *TrackedRows) Close() {
+	if !tr.drained && !tr.closed {
+		tr.report(ErrRowsNotDrained)
+	}
+	tr.closed = true
+	tr.Rows.Close()
+}
+
+// Check returns ErrRowsNotClosed if the rows are still open
+// and ErrRowsNotDrained if they were closed early.
+// It returns nil if all records were read, rows which failed w/ an error aren't drained.
+func (tr *TrackedRows) Check() error {
+	switch {
+	case tr.drained:
+		return nil
+	case tr.closed:
+		return ErrRowsNotDrained
+	default:
+		return ErrRowsNotClosed
+	}
+}
+
+func (tr *TrackedRows) report(err error) {
+	if tr.hook != nil {
+		tr.hook(err)
+	}
+}
+
+// WithEveryNth returns rows passing only every k-th record on: the first one, the k+1-th and so on.
+//
+// Meant for previews and estimations on huge results, pass the result to a collector:
+//
+//	sample, err := pgxscan.ScanAll[User](pgxscan.WithEveryNth(rows, 100))
+//
+// The other records are skipped by Next, pgx doesn't decode them.
+// For k <= 1 rows is returned as it is.
+func WithEveryNth(rows pgx.Rows, k int) pgx.Rows {
+	if k <= 1 {
+		return rows
+	}
+	return &everyNthRows{Rows: rows, k: k}
+}
+
+// everyNthRows passes every k-th record of Rows on.
+type everyNthRows struct {
+	pgx.Rows
+	k       int
+	started bool
+}
+
+// Next advances to the next sampled record, see pgx.Rows.
+func (r *everyNthRows) Next() bool {
+	if r.started {
+		for i := 1; i < r.k; i++ {
+			if !r.Rows.Next() {
+				return false
+			}
+		}
+	}
+	r.started = true
+	return r.Rows.Next()
+}
+
+// Finish closes rows and returns the error that ended the iteration, if any.
+//
+// pgx reports errors occurring while reading records, e.g. a lost connection,
+// only through rows.Err after Next returned false.
+// Call Finish after the loop so such errors are not lost:
+//
+//	for rows.Next() {
+//		...
+//	}
+//	if err := pgxscan.Finish(rows); err != nil {
+//		return err
+//	}
+func Finish(rows pgx.Rows) error {
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading rows: %w", err)
+	}
+	return nil
+}
+
+// finishCollect finishes rows for a collector, see Finish.
+// W/ PartialResults an error caused by a timeout is wrapped to match ErrPartialResult.
+func finishCollect(rows pgx.Rows) error {
+	err := Finish(rows)
+	if err != nil && PartialResults && (pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded)) {
+		return &partialError{err: err}
+	}
+	return err
+}
+
+// partialError is the error of a result ended early by a timeout.
+type partialError struct {
+	err error
+}
+
+func (e *partialError) Error() string {
+	return ErrPartialResult.Error() + ": " + e.err.Error()
+}
+
+// Is matches ErrPartialResult, the cause is matched through Unwrap.
+func (e *partialError) Is(target error) bool {
+	return target == ErrPartialResult
+}
+
+func (e *partialError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,242 @@
+package pgxscan_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
+)
+
+// fakeRows implements pgx.Rows, returning the records of testRows n times.
+type fakeRows struct {
+	testRows
+	n      int
+	closed bool
+}
+
+func (r *fakeRows) Close()                         { r.closed = true }
+func (r *fakeRows) CommandTag() pgconn.CommandTag  { return nil }
+func (r *fakeRows) Scan(dest ...interface{}) error { return nil }
+
+// RawValues returns the text representation of the values.
+func (r *fakeRows) RawValues() [][]byte {
+	raw := make([][]byte, len(r.vals))
+	for i, v := range r.vals {
+		if v != nil {
+			raw[i] = []byte(fmt.Sprint(v))
+		}
+	}
+	return raw
+}
+
+func (r *fakeRows) Next() bool {
+	if r.closed || r.n < 1 {
+		r.closed = true
+		return false
+	}
+	r.n--
+	return true
+}
+
+func TestTrackRows(t *testing.T) {
+	var reported []error
+	hook := func(err error) {
+		reported = append(reported, err)
+	}
+
+	// fully read rows
+	tr := pgxscan.TrackRows(&fakeRows{testRows: mkTestRows(), n: 2}, hook)
+	if tr.Check() != pgxscan.ErrRowsNotClosed {
+		t.Error("open rows not detected")
+	}
+	for tr.Next() {
+		var dest struct {
+			Bigid int64
+		}
+		err := pgxscan.ReadStruct(&dest, tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Close()
+	if err := tr.Check(); err != nil {
+		t.Errorf("drained rows reported, error: %v", err)
+	}
+	if len(reported) != 0 {
+		t.Errorf("unexpected reports: %v", reported)
+	}
+
+	// closed early
+	tr = pgxscan.TrackRows(&fakeRows{testRows: mkTestRows(), n: 2}, hook)
+	tr.Next()
+	tr.Close()
+	if tr.Check() != pgxscan.ErrRowsNotDrained {
+		t.Error("undrained rows not detected")
+	}
+	if len(reported) != 1 || reported[0] != pgxscan.ErrRowsNotDrained {
+		t.Errorf("undrained rows not reported: %v", reported)
+	}
+
+	// failed w/ an error
+	failing := &fakeRows{testRows: mkTestRows(), n: 2}
+	failing.errSet = errors.New("connection lost")
+	tr = pgxscan.TrackRows(failing, hook)
+	for tr.Next() {
+	}
+	if tr.Check() != pgxscan.ErrRowsNotDrained {
+		t.Error("failed rows reported as drained")
+	}
+}
+
+func TestRawValueObserver(t *testing.T) {
+	var seen []string
+	pgxscan.RawValueObserver = func(col string, oid uint32, raw []byte) {
+		seen = append(seen, col+"="+string(raw))
+	}
+	defer func() { pgxscan.RawValueObserver = nil }()
+
+	var dest struct {
+		Bigid int64
+	}
+	rows := &fakeRows{testRows: mkTestRows(), n: 1}
+	rows.Next()
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != len(rows.fds) || seen[0] != "bigid=703340046535533321" {
+		t.Errorf("unexpected observations: %v", seen)
+	}
+
+	// rows w/o raw values are not observed
+	seen = nil
+	err = pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 0 {
+		t.Errorf("unexpected observations: %v", seen)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	rows := &fakeRows{testRows: mkTestRows(), n: 1}
+	for rows.Next() {
+	}
+	if err := pgxscan.Finish(rows); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	errLost := errors.New("connection lost")
+	rows = &fakeRows{testRows: mkTestRows(), n: 1}
+	rows.errSet = errLost
+	err := pgxscan.Finish(rows)
+	if !errors.Is(err, errLost) {
+		t.Errorf("rows error not returned: %v", err)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+}
+
+// timeoutRows ends w/ a timeout after the records of fakeRows, like pgx if the deadline passed.
+type timeoutRows struct {
+	*fakeRows
+}
+
+func (r timeoutRows) Err() error {
+	if r.closed {
+		return fmt.Errorf("timeout: %w", context.DeadlineExceeded)
+	}
+	return nil
+}
+
+func TestPartialResults(t *testing.T) {
+	type pair struct {
+		ID   int64
+		Name string
+	}
+	rows := func() timeoutRows {
+		return timeoutRows{&fakeRows{testRows: mkPairRows(), n: 2}}
+	}
+
+	// w/o PartialResults the timeout is an error
+	var dest []pair
+	err := pgxscan.ReadStructs(&dest, rows())
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, pgxscan.ErrPartialResult) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if dest != nil {
+		t.Errorf("destination modified: %v", dest)
+	}
+
+	pgxscan.PartialResults = true
+	defer func() { pgxscan.PartialResults = false }()
+
+	err = pgxscan.ReadStructs(&dest, rows())
+	if !errors.Is(err, pgxscan.ErrPartialResult) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("partial result not reported, error: %v", err)
+	}
+	if len(dest) != 2 {
+		t.Errorf("records read before the timeout dropped: %v", dest)
+	}
+
+	pairs, err := pgxscan.ScanAll[pair](rows())
+	if !errors.Is(err, pgxscan.ErrPartialResult) || len(pairs) != 2 {
+		t.Errorf("partial result not returned by ScanAll: %v, error: %v", pairs, err)
+	}
+	tuples, err := pgxscan.ScanTuples2[int64, string](rows())
+	if !errors.Is(err, pgxscan.ErrPartialResult) || len(tuples) != 2 {
+		t.Errorf("partial result not returned by ScanTuples2: %v, error: %v", tuples, err)
+	}
+
+	// other errors are no partial results
+	_, err = pgxscan.ScanAll[pair](&fakeRows{testRows: testRows{fds: mkPairRows().fds, vals: []interface{}{"x", "y"}}, n: 1})
+	if err == nil || errors.Is(err, pgxscan.ErrPartialResult) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// countingRows counts the records decoded by Values.
+type countingRows struct {
+	*fakeRows
+	decoded int
+}
+
+func (r *countingRows) Values() ([]interface{}, error) {
+	r.decoded++
+	return r.fakeRows.Values()
+}
+
+func TestWithEveryNth(t *testing.T) {
+	rows := &countingRows{fakeRows: &fakeRows{testRows: mkPairRows(), n: 7}}
+	ids, err := pgxscan.ScanAll[int64](pgxscan.WithEveryNth(&fakeRows{testRows: mkColumnRows("id", pgtype.Int8OID, int64(1)), n: 7}, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// records 1, 4 and 7
+	if len(ids) != 3 {
+		t.Errorf("unexpected sample: %v", ids)
+	}
+
+	tuples, err := pgxscan.ScanTuples2[int64, string](pgxscan.WithEveryNth(rows, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// records 1 and 5
+	if len(tuples) != 2 || rows.decoded != 2 {
+		t.Errorf("unexpected sample: %v, %d records decoded", tuples, rows.decoded)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+
+	all := &fakeRows{testRows: mkPairRows(), n: 2}
+	if pgxscan.WithEveryNth(all, 1) != all {
+		t.Error("rows wrapped for k = 1")
+	}
+}
@@ -1,11 +1,18 @@
 package pgxscan
 
 import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
+	"runtime/debug"
 	"strings"
+	"time"
 
+	"github.com/guidog/pgxscan/core"
 	"github.com/jackc/pgproto3/v2"
 	"github.com/jackc/pgtype"
 )
@@ -15,34 +22,112 @@ import (
 // If the names match true is returned, false otherwise.
 type NameMatcherFnc func(fieldName, resultName string) bool
 
+// RawValueObserverFnc is the signature for a function observing the raw column values of a record.
+// col is the column name, oid its data type and raw the undecoded value, nil for NULL.
+// raw is only valid during the call.
+type RawValueObserverFnc func(col string, oid uint32, raw []byte)
+
 // PgxRows is a subset of the pgx.Rows interface.
 //
 // Used to create a smaller API to implement for tests.
+// Every pgx.Rows is a PgxRows, so mocked rows, e.g. from pgxmock, can be passed to ReadStruct as is.
 type PgxRows interface {
 	FieldDescriptions() []pgproto3.FieldDescription
 	Values() ([]interface{}, error)
 	Err() error
 }
 
-const errMismatchFmt = "field %s can't hold result %s, %w"
+const errMismatchFmt = "field %s can't hold result %s, %v"
+
+// ScanError is returned by ReadStruct if a DB value can not be assigned to a struct field.
+//
+// It wraps the actual error, so errors.Is(err, ErrInvalidDestination) works as before.
+type ScanError struct {
+	// Field is the name of the struct field, empty if the destination is not a struct field.
+	Field string
+	// Column is the name of the result column.
+	Column string
+	// Err is the reason the assignment failed.
+	Err error
+	// Panic holds the recovered value if the assignment panicked, e.g. in a registered converter.
+	Panic interface{}
+	// Stack is the stack trace of the panic.
+	Stack []byte
+}
+
+func (e *ScanError) Error() string {
+	msg := e.Err.Error()
+	if len(e.Field) > 0 {
+		msg = fmt.Sprintf(errMismatchFmt, e.Field, e.Column, e.Err)
+	}
+	if e.Panic != nil {
+		msg += fmt.Sprintf(" (panic: %v)", e.Panic)
+	}
+	return msg
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
 
 var (
 	// ErrNotPointer is returend when the destination is not a pointer.
-	ErrNotPointer = errors.New("arg not a pointer")
+	ErrNotPointer = core.ErrNotPointer
 	// ErrNotStruct is returned when the dereferenced destination pointer does not point to a struct.
-	ErrNotStruct = errors.New("arg not a struct")
+	ErrNotStruct = core.ErrNotStruct
 	// ErrDestNil is returned when the destination is nil or points to nothing.
-	ErrDestNil = errors.New("destination is nil")
+	ErrDestNil = core.ErrDestNil
 	// ErrNotSimpleSlice is returned if the destination field is a slice
 	ErrNotSimpleSlice = errors.New("db field not a simple slice")
 	// ErrEmptyStruct is returned if the destination struct has no fields
-	ErrEmptyStruct = errors.New("destination struct has no fields")
+	ErrEmptyStruct = core.ErrEmptyStruct
 	// ErrInvalidDestination is returned when the destination field does not match the DB type
-	ErrInvalidDestination = errors.New("destination has incompatible type")
+	ErrInvalidDestination = core.ErrInvalidDestination
+	// ErrOutOfRange is returned when a DB value does not fit into the destination field
+	ErrOutOfRange = errors.New("value out of range for destination")
 
 	// DefaultNameMatcher is the matching function used by ReadStruct.
 	// If not set, the internal matching is used.
 	DefaultNameMatcher NameMatcherFnc = nil
+
+	// RawValueObserver is called by ReadStruct for every column before the values are decoded.
+	// It is only called if rows provide the raw values with a RawValues() [][]byte method, like pgx.Rows.
+	// If not set, nothing is called.
+	RawValueObserver RawValueObserverFnc = nil
+
+	// CockroachDBMode relaxes the assignment rules for CockroachDB.
+	// CockroachDB's INT is a 64 bit integer, so in this mode bigint results are
+	// also assigned to int32 and int16 fields if the value fits.
+	CockroachDBMode = false
+
+	// TrimChar removes the padding of char(n) values, the trailing spaces are trimmed.
+	TrimChar = false
+
+	// EmptyAsNull assigns empty text values as NULL to fields which can hold NULL,
+	// like pointers, sql.NullString or pgtype.Text. Other fields still get "".
+	// Meant for imported data storing '' where NULL is meant.
+	EmptyAsNull = false
+
+	// ParseText allows text values in numeric fields, they are parsed w/ strconv.
+	// Meant for schemas storing numbers as text.
+	ParseText = false
+
+	// FormatNumbers allows numeric values in string fields, they are formatted like Postgres does.
+	// Meant for APIs which treat IDs as strings.
+	FormatNumbers = false
+
+	// CoerceBool allows integer and text values in bool fields, like smallint 0/1 or 't'/'f'.
+	// Meant for legacy schemas w/o boolean columns.
+	CoerceBool = false
+
+	// ZeroCopyBytes assigns bytea values w/o copying them.
+	// The values then share the read buffer of pgx, which is overwritten by the next call of rows.Next.
+	// Meant for pipelines processing every record before reading the next one.
+	ZeroCopyBytes = false
+
+	// TimeLocation is the location timestamptz values are converted to, e.g. time.UTC.
+	// If not set, the values keep the location pgx returns them in.
+	TimeLocation *time.Location = nil
 )
 
 // ReadStruct scans the current record in rows into the given destination.
@@ -53,14 +138,22 @@ var (
 //
 // If a struct field cannot be modified it is silently ignored.
 //
-// If a DB value can not be assigned to the destination field an ErrInvalidDestination error
-// or an error wrapping ErrInvalidDestination is returned.
+// If a DB value can not be assigned to the destination field a *ScanError is returned.
+// It wraps the reason, usually ErrInvalidDestination.
 //
 // Error checking is best done w/ errors.Is().
 //
 // ReadStruct uses DefaultNameMatcher to match struct fields to result columns.
 // If it is not set, the internal matching is used.
+//
+// A function registered with RegisterPostProcessor for the struct type is called
+// for every matched field before the value is assigned.
 func ReadStruct(dest interface{}, rows PgxRows) error {
+	return readStruct(dest, rows, nil)
+}
+
+// readStruct implements ReadStruct, st tracks the size of the result for the collectors.
+func readStruct(dest interface{}, rows PgxRows, st *sizeTracker) error {
 	// bail out early if something is fishy
 	if dest == nil {
 		return ErrDestNil
@@ -69,70 +162,39 @@ func ReadStruct(dest interface{}, rows PgxRows) error {
 		return rows.Err()
 	}
 
-	// check for pointer
-	t := reflect.TypeOf(dest)
-	if k := t.Kind(); k != reflect.Ptr {
-		return ErrNotPointer
-	}
-
-	// see if dest points to nothing
-	sval := reflect.ValueOf(dest)
-	if sval.IsNil() {
-		return ErrDestNil
-	}
-
-	// get handle to struct after we're sure dest is a valid pointer
-	structData := sval.Elem()
-	if k := structData.Kind(); k != reflect.Struct {
-		return ErrNotStruct
-	}
-
-	// no destination fields, return
-	if structData.NumField() < 1 {
-		return ErrEmptyStruct
+	structData, err := core.StructOf(dest)
+	if err != nil {
+		return err
 	}
 
 	// collect all field names from struct
-	structFields := make([]string, 0, 20) // preallocate, enough for most structs
-	getFields(structData.Type(), &structFields)
+	structFields := core.Fields(structData.Type())
 
 	// field descriptions and values of result set are in sync
 	// so fds[i] is matched by vals[i]
 	fds := rows.FieldDescriptions()
-	vals, err := rows.Values()
+	if RawValueObserver != nil {
+		observeRaw(fds, rows)
+	}
+	fieldNames := matchColumns(structFields, fds, structMatcher(structData.Type()), columnMapping(structData.Type()))
+	lazy := lazyColumns(structData, fieldNames)
+
+	vals, err := readValues(rows, lazy, statsFor(structData.Type()))
 	if err != nil {
 		return err
 	}
 
-	var matchFnc NameMatcherFnc
-
-	if DefaultNameMatcher == nil {
-		matchFnc = defaultNameMatcher
-	} else {
-		matchFnc = DefaultNameMatcher
+	if err := checkSize(vals, st); err != nil {
+		return err
 	}
 
-	// loop over all sql values and try to find a matching struct field
+	tags := tagsOf(structData.Type())
+	postProcess := postProcessorFor(structData.Type())
+
+	// loop over all sql values and assign them to the matching struct field
 	// ignore missing struct fields
-	for i := 0; i < len(fds) && len(structFields) > 0; i++ {
-		fd := fds[i]
-		resultName := string(fd.Name) // fd.Name is []byte
-		fieldName := ""
-
-		// match names
-		for i, k := range structFields {
-			if matchFnc(k, resultName) {
-				// names do match
-				fieldName = k
-				// remove found field
-				l := len(structFields) - 1
-				if l > 0 {
-					structFields[i] = structFields[l]
-				}
-				structFields = structFields[:l]
-				break
-			}
-		}
+	for i := range fds {
+		fieldName := fieldNames[i]
 
 		if len(fieldName) < 1 {
 			// no matching field found, next
@@ -146,164 +208,943 @@ func ReadStruct(dest interface{}, rows PgxRows) error {
 			// silently ignore fields that can not be set
 			continue
 		}
+		if lazy != nil && lazy[i] {
+			destField.Addr().Interface().(lazySetter).setLazy(vals[i], &fds[i])
+			continue
+		}
 
-		// fetch value for column[i]
 		v := vals[i]
-
-		switch v := v.(type) {
-		// special cases for common arrays/slices
-		// fresh slices are assigned to the destination
-		case pgtype.TextArray:
-			if !isStringSlice(destField) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+		if layout := tags[fieldName].Layout; layout != "" {
+			v, err = parseLayout(v, layout)
+			if err != nil {
+				return &ScanError{Field: fieldName, Column: string(fds[i].Name), Err: err}
 			}
-			if len(v.Dimensions) != 1 {
-				return ErrNotSimpleSlice
+		}
+		if postProcess != nil {
+			err = guard(fieldName, &fds[i], func() (err error) {
+				v, err = postProcess(fieldName, v)
+				return err
+			})
+			if err != nil {
+				return err
 			}
-			res := make([]string, len(v.Elements))
-			for i := 0; i < len(res); i++ {
-				res[i] = v.Elements[i].String
+		}
+		if nv := tags[fieldName].NullValue; v == nil && nv != nil {
+			err = guard(fieldName, &fds[i], func() error {
+				return assignNullValue(destField, *nv)
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = assignField(destField, v, &fds[i], fieldName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// assignField assigns the DB value v to the struct field dest.
+// Errors are returned as *ScanError.
+// Panics are recovered and reported in the ScanError.
+func assignField(dest reflect.Value, v interface{}, fd *pgproto3.FieldDescription, fieldName string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicScanError(fieldName, fd, r, debug.Stack())
+		}
+	}()
+
+	if dec := typeDecoderFor(fd.DataTypeOID); dec != nil && v != nil {
+		v, err = dec(v)
+	}
+	if err == nil {
+		err = checkEnum(fd.DataTypeOID, v)
+	}
+	v = normalizeValue(dest, v, fd.DataTypeOID)
+	if err == nil && fd.DataTypeOID == moneyOID && v != nil && dest.Kind() != reflect.Interface {
+		v, err = moneyNumeric(v, fd.Format)
+	}
+	if err == nil {
+		err = assignValue(dest, v, fd)
+	}
+	if err != nil {
+		var perr *panicError
+		if errors.As(err, &perr) {
+			return panicScanError(fieldName, fd, perr.value, perr.stack)
+		}
+		return &ScanError{
+			Field:  fieldName,
+			Column: string(fd.Name),
+			Err:    err,
+		}
+	}
+	return nil
+}
+
+// guard calls f, which runs user code for the field, e.g. a post processor.
+// Errors are returned as *ScanError, panics are recovered and reported in the ScanError.
+func guard(fieldName string, fd *pgproto3.FieldDescription, f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicScanError(fieldName, fd, r, debug.Stack())
+		}
+	}()
+
+	if err := f(); err != nil {
+		return &ScanError{Field: fieldName, Column: string(fd.Name), Err: err}
+	}
+	return nil
+}
+
+// panicScanError returns the ScanError for the panic r while assigning the field.
+func panicScanError(fieldName string, fd *pgproto3.FieldDescription, r interface{}, stack []byte) *ScanError {
+	return &ScanError{
+		Field:  fieldName,
+		Column: string(fd.Name),
+		Err:    ErrInvalidDestination,
+		Panic:  r,
+		Stack:  stack,
+	}
+}
+
+// normalizeValue applies the options adjusting values before they are assigned to dest:
+// TimeLocation, TrimChar and EmptyAsNull.
+// oid is the type of v, for array elements and range bounds see elementFD.
+func normalizeValue(dest reflect.Value, v interface{}, oid uint32) interface{} {
+	if t, ok := v.(time.Time); ok && TimeLocation != nil && oid == pgtype.TimestamptzOID {
+		v = t.In(TimeLocation)
+	}
+	if s, ok := v.(string); ok && TrimChar && oid == pgtype.BPCharOID {
+		v = strings.TrimRight(s, " ")
+	}
+	if s, ok := v.(string); ok && EmptyAsNull && s == "" && isNullable(dest) {
+		v = nil
+	}
+	return v
+}
+
+// elementOIDs maps arrays and ranges to the type of their elements.
+var elementOIDs = map[uint32]uint32{
+	pgtype.ACLItemArrayOID:     pgtype.ACLItemOID,
+	pgtype.BoolArrayOID:        pgtype.BoolOID,
+	pgtype.BPCharArrayOID:      pgtype.BPCharOID,
+	pgtype.ByteaArrayOID:       pgtype.ByteaOID,
+	pgtype.CIDRArrayOID:        pgtype.CIDROID,
+	pgtype.DateArrayOID:        pgtype.DateOID,
+	pgtype.Float4ArrayOID:      pgtype.Float4OID,
+	pgtype.Float8ArrayOID:      pgtype.Float8OID,
+	pgtype.InetArrayOID:        pgtype.InetOID,
+	pgtype.Int2ArrayOID:        pgtype.Int2OID,
+	pgtype.Int4ArrayOID:        pgtype.Int4OID,
+	pgtype.Int8ArrayOID:        pgtype.Int8OID,
+	pgtype.JSONBArrayOID:       pgtype.JSONBOID,
+	pgtype.NumericArrayOID:     pgtype.NumericOID,
+	pgtype.TextArrayOID:        pgtype.TextOID,
+	pgtype.TimestampArrayOID:   pgtype.TimestampOID,
+	pgtype.TimestamptzArrayOID: pgtype.TimestamptzOID,
+	pgtype.UUIDArrayOID:        pgtype.UUIDOID,
+	pgtype.VarcharArrayOID:     pgtype.VarcharOID,
+	intervalArrayOID:           pgtype.IntervalOID,
+	pgtype.DaterangeOID:        pgtype.DateOID,
+	pgtype.Int4rangeOID:        pgtype.Int4OID,
+	pgtype.Int8rangeOID:        pgtype.Int8OID,
+	pgtype.NumrangeOID:         pgtype.NumericOID,
+	pgtype.TsrangeOID:          pgtype.TimestampOID,
+	pgtype.TstzrangeOID:        pgtype.TimestamptzOID,
+}
+
+// elementOID returns the type of the elements of the array or range type oid, 0 if it is unknown.
+// Arrays of registered enum and composite types are included.
+func elementOID(oid uint32) uint32 {
+	if e, ok := elementOIDs[oid]; ok {
+		return e
+	}
+	if e := compositeElementOID(oid); e != 0 {
+		return e
+	}
+	return enumElementOID(oid)
+}
+
+// elementFD returns the description of the elements of the array or range column fd.
+// OID based decisions, like for json elements, need the type of the elements.
+func elementFD(fd *pgproto3.FieldDescription) *pgproto3.FieldDescription {
+	return &pgproto3.FieldDescription{Name: fd.Name, DataTypeOID: elementOID(fd.DataTypeOID), Format: fd.Format}
+}
+
+// assignValue assigns the DB value v to dest.
+// fd describes the column v is from.
+func assignValue(dest reflect.Value, v interface{}, fd *pgproto3.FieldDescription) error {
+	// registered converters go first
+	if conv := lookupConverter(dest.Type()); conv != nil {
+		return conv(dest, v)
+	}
+
+	// interface fields get the value as it is, if it implements the interface
+	if dest.Kind() == reflect.Interface {
+		if v == nil {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		if p, ok := v.(ltreePath); ok {
+			// internal type, pgx returns a string
+			v = string(p)
+		}
+		if !reflect.TypeOf(v).Implements(dest.Type()) {
+			return ErrInvalidDestination
+		}
+		if !ZeroCopyBytes {
+			v = copyBytea(v)
+		}
+		dest.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	// Null types and NullAssigners hold NULL w/o a pointer
+	if dest.CanAddr() {
+		if ns, ok := dest.Addr().Interface().(nullSetter); ok {
+			return ns.setNull(v, fd)
+		}
+		if na, ok := dest.Addr().Interface().(NullAssigner); ok {
+			return assignNullAssigner(na, v)
+		}
+	}
+
+	// Array types keep the dimensions
+	if dest.CanAddr() {
+		if as, ok := dest.Addr().Interface().(arraySetter); ok {
+			return as.setArray(v, fd)
+		}
+	}
+
+	if im, ok := v.(pgtype.InfinityModifier); ok {
+		if done, err := assignInfinity(dest, im); done {
+			return err
+		}
+	}
+
+	if ProtobufMode && isProtoWrapper(dest.Type()) {
+		return assignProtoWrapper(dest, v, fd)
+	}
+	if ProtobufMode && isProtoTimestamp(dest.Type()) {
+		return assignProtoTimestamp(dest, v)
+	}
+
+	// NULL is nil for pointer types
+	// other values are assigned to a newly allocated target
+	if dest.Kind() == reflect.Ptr {
+		if v == nil {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		if !isBigNumber(dest.Type()) {
+			nv := reflect.New(dest.Type().Elem())
+			err := assignValue(nv.Elem(), v, fd)
+			if err != nil {
+				return err
 			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.Int2Array:
-			if !isIntSlice(destField, 2) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+			dest.Set(nv)
+			return nil
+		}
+	}
+
+	// pgtype values keep the NULL status
+	// same types are copied, others converted by the pgtype value
+	if dest.CanAddr() {
+		if pv, ok := dest.Addr().Interface().(pgtype.Value); ok {
+			if v != nil && reflect.TypeOf(v) == dest.Type() {
+				// arrays keep their dimensions and element status
+				if !ZeroCopyBytes {
+					v = copyBytea(v)
+				}
+				dest.Set(reflect.ValueOf(v))
+				return nil
 			}
-			// sql returned 16 bit ints
-			if len(v.Dimensions) != 1 {
-				return ErrNotSimpleSlice
+			return setPgtype(pv, v)
+		}
+	}
+
+	// types for database/sql, like sql.NullString, scan themselves
+	// they expect json as encoded document
+	if dest.CanAddr() {
+		if sc, ok := dest.Addr().Interface().(sql.Scanner); ok {
+			if isJSON(fd.DataTypeOID) && v != nil {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				v = b
 			}
-			res := make([]int16, len(v.Elements))
-			for i := 0; i < len(res); i++ {
-				res[i] = int16(v.Elements[i].Int)
+			return scan(sc, v)
+		}
+	}
+
+	// bytea into types decoding themselves, like hashes or serialized messages
+	if b, ok := v.([]byte); ok && dest.CanAddr() {
+		if bu, ok := dest.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+			return bu.UnmarshalBinary(b)
+		}
+	}
+
+	// json values are already decoded by pgx
+	// re-encode them for destinations that want the raw document, a struct or a slice of structs
+	if isJSON(fd.DataTypeOID) && (isBytes(dest) || isStructLike(dest) || isStructSlice(dest)) {
+		return assignJSON(dest, v)
+	}
+
+	// xml is unknown to pgtype and returned as text
+	if fd.DataTypeOID == xmlOID && v != nil {
+		return assignXML(dest, v)
+	}
+
+	if fd.DataTypeOID == timetzOID && v != nil && dest.Type() == timeTZType {
+		return assignTimeTZ(dest, v)
+	}
+
+	if fd.DataTypeOID == lsnOID && v != nil {
+		return assignLSN(dest, v)
+	}
+
+	if regTypes[fd.DataTypeOID] && v != nil {
+		return assignRegValue(dest, v)
+	}
+
+	if CoerceBool && dest.Kind() == reflect.Bool && v != nil {
+		if _, ok := v.(bool); !ok {
+			b, ok := coerceBool(v)
+			if !ok {
+				return fmt.Errorf("%w: %v is not a bool", ErrInvalidDestination, v)
 			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.Int4Array:
-			if !isIntSlice(destField, 4) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+			dest.SetBool(b)
+			return nil
+		}
+	}
+
+	if FormatNumbers && dest.Kind() == reflect.String {
+		if s, ok := formatNumber(v); ok {
+			dest.SetString(s)
+			return nil
+		}
+	}
+
+	if done, err := assignArray(dest, v, fd); done {
+		return err
+	}
+
+	switch v := v.(type) {
+	// special cases for common arrays/slices
+	// fresh slices are assigned to the destination
+	case pgtype.TextArray:
+		return assignStringSlice(dest, v.Dimensions, len(v.Elements), func(i int) string {
+			return v.Elements[i].String
+		})
+	case pgtype.VarcharArray:
+		return assignStringSlice(dest, v.Dimensions, len(v.Elements), func(i int) string {
+			return v.Elements[i].String
+		})
+	case pgtype.BPCharArray:
+		return assignStringSlice(dest, v.Dimensions, len(v.Elements), func(i int) string {
+			if TrimChar {
+				return strings.TrimRight(v.Elements[i].String, " ")
 			}
-			// sql returned 32 bit ints
-			if len(v.Dimensions) != 1 {
+			return v.Elements[i].String
+		})
+	case pgtype.Int2Array:
+		if isCheckedIntSlice(dest) || isWideIntSlice(dest, 2) {
+			if !isSimpleArray(v.Dimensions, len(v.Elements)) {
 				return ErrNotSimpleSlice
 			}
-			res := make([]int32, len(v.Elements))
-			for i := 0; i < len(res); i++ {
-				res[i] = int32(v.Elements[i].Int)
+			ints := make([]int64, len(v.Elements))
+			for i := 0; i < len(ints); i++ {
+				ints[i] = int64(v.Elements[i].Int)
 			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.Int8Array:
-			if !isIntSlice(destField, 8) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
-			}
-			// sql returned 64 bit ints
-			if len(v.Dimensions) != 1 {
+			return assignIntSlice(dest, ints)
+		}
+		if !isIntSlice(dest, 2) {
+			return ErrInvalidDestination
+		}
+		// sql returned 16 bit ints
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[int16](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = int16(v.Elements[i].Int)
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.Int4Array:
+		if isCheckedIntSlice(dest) || isWideIntSlice(dest, 4) {
+			if !isSimpleArray(v.Dimensions, len(v.Elements)) {
 				return ErrNotSimpleSlice
 			}
-			res := make([]int64, len(v.Elements))
-			for i := 0; i < len(res); i++ {
-				res[i] = int64(v.Elements[i].Int)
-			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.Float4Array:
-			if !isFloatSlice(destField, 4) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+			ints := make([]int64, len(v.Elements))
+			for i := 0; i < len(ints); i++ {
+				ints[i] = int64(v.Elements[i].Int)
 			}
-			if len(v.Dimensions) != 1 {
+			return assignIntSlice(dest, ints)
+		}
+		if !isIntSlice(dest, 4) {
+			return ErrInvalidDestination
+		}
+		// sql returned 32 bit ints
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[int32](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = int32(v.Elements[i].Int)
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.Int8Array:
+		if isCheckedIntSlice(dest) {
+			if !isSimpleArray(v.Dimensions, len(v.Elements)) {
 				return ErrNotSimpleSlice
 			}
-			res := make([]float32, len(v.Elements))
-			for i := 0; i < len(res); i++ {
-				res[i] = float32(v.Elements[i].Float)
-			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.Float8Array:
-			if !isFloatSlice(destField, 8) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+			ints := make([]int64, len(v.Elements))
+			for i := 0; i < len(ints); i++ {
+				ints[i] = int64(v.Elements[i].Int)
 			}
-			if len(v.Dimensions) != 1 {
+			return assignIntSlice(dest, ints)
+		}
+		if !isIntSlice(dest, 8) {
+			return ErrInvalidDestination
+		}
+		// sql returned 64 bit ints
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[int64](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = int64(v.Elements[i].Int)
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.Float4Array:
+		if isFloatSlice(dest, 8) {
+			// widen to float64, which is exact
+			if !isSimpleArray(v.Dimensions, len(v.Elements)) {
 				return ErrNotSimpleSlice
 			}
-			res := make([]float64, len(v.Elements))
+			res := sliceFor[float64](dest, len(v.Elements))
 			for i := 0; i < len(res); i++ {
 				res[i] = float64(v.Elements[i].Float)
 			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		case pgtype.ByteaArray:
-			if !isBytesSlice(destField) {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, ErrInvalidDestination)
+			setSlice(dest, reflect.ValueOf(res))
+			return nil
+		}
+		if !isFloatSlice(dest, 4) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[float32](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = float32(v.Elements[i].Float)
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.Float8Array:
+		if !isFloatSlice(dest, 8) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[float64](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = float64(v.Elements[i].Float)
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.BoolArray:
+		if !isBoolSlice(dest) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[bool](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			res[i] = v.Elements[i].Bool
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.TimestampArray:
+		if !isTimeSlice(dest) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[time.Time](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			t, err := elementTime(v.Elements[i].Time, v.Elements[i].InfinityModifier)
+			if err != nil {
+				return err
 			}
-			// [][]byte is bytea[] in Postgres
-			if len(v.Dimensions) != 1 {
-				return ErrNotSimpleSlice
+			res[i] = t
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.TimestamptzArray:
+		if !isTimeSlice(dest) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[time.Time](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			t, err := elementTime(v.Elements[i].Time, v.Elements[i].InfinityModifier)
+			if err != nil {
+				return err
 			}
-			res := make([][]byte, len(v.Elements))
-			// need to copy bytes over
-			for i := 0; i < len(res); i++ {
-				a := make([]byte, len(v.Elements[i].Bytes))
-				copy(a, v.Elements[i].Bytes)
-				res[i] = a
+			if TimeLocation != nil {
+				t = t.In(TimeLocation)
 			}
-			vres := reflect.ValueOf(res)
-			destField.Set(vres)
-		default:
-			sqlVal := reflect.ValueOf(v)
-			err := assign(destField, sqlVal)
+			res[i] = t
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.DateArray:
+		if !isTimeSlice(dest) {
+			return ErrInvalidDestination
+		}
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[time.Time](dest, len(v.Elements))
+		for i := 0; i < len(res); i++ {
+			t, err := elementTime(v.Elements[i].Time, v.Elements[i].InfinityModifier)
 			if err != nil {
-				return fmt.Errorf(errMismatchFmt, fieldName, resultName, err)
+				return err
 			}
+			res[i] = t
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case pgtype.UUIDArray:
+		if !isUUIDSlice(dest) {
+			return ErrInvalidDestination
+		}
+		// elements are of any [16]byte type, like uuid.UUID
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := makeSlice(dest, len(v.Elements))
+		for i := 0; i < len(v.Elements); i++ {
+			reflect.Copy(res.Index(i), reflect.ValueOf(v.Elements[i].Bytes[:]))
+		}
+		dest.Set(res)
+	case pgtype.NumericArray:
+		return assignNumericSlice(dest, v)
+	case pgtype.InetArray, pgtype.CIDRArray:
+		// every element is assigned like an inet column
+		if dest.Kind() != reflect.Slice {
+			return ErrInvalidDestination
 		}
+		elems, dims, _ := arrayElements(v)
+		return assignElements(dest, elems, dims, fd)
+	case pgtype.ByteaArray:
+		if !isBytesSlice(dest) {
+			return ErrInvalidDestination
+		}
+		// [][]byte is bytea[] in Postgres
+		if !isSimpleArray(v.Dimensions, len(v.Elements)) {
+			return ErrNotSimpleSlice
+		}
+		res := sliceFor[[]byte](dest, len(v.Elements))
+		// need to copy bytes over, unless aliasing is wanted
+		for i := 0; i < len(res); i++ {
+			if ZeroCopyBytes {
+				res[i] = v.Elements[i].Bytes
+				continue
+			}
+			a := make([]byte, len(v.Elements[i].Bytes))
+			copy(a, v.Elements[i].Bytes)
+			res[i] = a
+		}
+		setSlice(dest, reflect.ValueOf(res))
+	case nil:
+		if NilSlices && dest.Kind() == reflect.Slice {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		// NULL can only be assigned to pointers, handled above
+		return ErrInvalidDestination
+	case map[string]interface{}:
+		// composite type
+		if dest.Kind() != reflect.Struct {
+			return core.Assign(dest, reflect.ValueOf(v))
+		}
+		return assignComposite(dest, v, fd)
+	case []interface{}:
+		// array of a type w/o pgtype array, like a composite or interval
+		if dest.Kind() == reflect.Array {
+			return assignFixedArray(dest, v, fd)
+		}
+		return assignCompositeSlice(dest, v, fd)
+	case *net.IPNet:
+		return assignInet(dest, v)
+	case map[string]pgtype.Text:
+		// hstore
+		return assignHstore(dest, v)
+	case pgtype.Interval:
+		switch dest.Type() {
+		case durationType:
+			return assignDuration(dest, v)
+		case intervalType:
+			dest.Set(reflect.ValueOf(Interval{Months: v.Months, Days: v.Days, Microseconds: v.Microseconds}))
+			return nil
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case pgtype.Int4range:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case pgtype.Int8range:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case pgtype.Numrange:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case pgtype.Tsrange:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case pgtype.Tstzrange:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case pgtype.Daterange:
+		return assignRange(dest, &v.Lower, &v.Upper, v.LowerType, v.UpperType, fd)
+	case string:
+		if ParseText && isNumber(dest) {
+			return parseNumber(dest, v)
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case []byte:
+		// bytea into any slice of byte kind, e.g. type SHA256 []byte
+		if isBytes(dest) {
+			if !ZeroCopyBytes {
+				v = append([]byte{}, v...)
+			}
+			dest.SetBytes(v)
+			return nil
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case ltreePath:
+		return assignLtree(dest, v)
+	case pgtype.Varbit:
+		return assignBits(dest, v)
+	case pgtype.Point:
+		return assignPoint(dest, v.P)
+	case pgtype.Box, pgtype.Circle, pgtype.Line, pgtype.Path, pgtype.Polygon:
+		return assignGeometry(dest, v)
+	case pgtype.Numeric:
+		if isBigNumber(dest.Type()) {
+			return assignBig(dest, v)
+		}
+		if fd.DataTypeOID == moneyOID && dest.Kind() == reflect.Int64 {
+			// money in cents
+			dest.SetInt(v.Int.Int64())
+			return nil
+		}
+		if isInteger(dest) && fd.DataTypeOID != moneyOID {
+			return assignNumericInt(dest, v)
+		}
+		if NumericFloats && (dest.Kind() == reflect.Float64 || dest.Kind() == reflect.Float32) {
+			f, err := numericFloat(v)
+			if err != nil {
+				return err
+			}
+			dest.SetFloat(f)
+			return nil
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case int64:
+		if isUint(dest) {
+			return assignUint(dest, v)
+		}
+		if isPlainInt(dest) {
+			return assignInt(dest, v)
+		}
+		if dest.Type() == timeOfDayType || (dest.Type() == timeType && fd.DataTypeOID == pgtype.TimeOID) {
+			return assignTimeOfDay(dest, v, fd)
+		}
+		if CockroachDBMode && (isIntSize(dest.Type(), 4) || isIntSize(dest.Type(), 2)) {
+			if dest.OverflowInt(v) {
+				return ErrOutOfRange
+			}
+			dest.SetInt(v)
+			return nil
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case time.Time:
+		if dest.Type() == dateType {
+			dest.Set(reflect.ValueOf(DateOf(v)))
+			return nil
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case uint32:
+		// oid
+		return assignOID(dest, v)
+	case int32:
+		if isUint(dest) {
+			return assignUint(dest, int64(v))
+		}
+		if isPlainInt(dest) {
+			return assignInt(dest, int64(v))
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	case int16:
+		if isUint(dest) {
+			return assignUint(dest, int64(v))
+		}
+		if isPlainInt(dest) {
+			return assignInt(dest, int64(v))
+		}
+		return core.Assign(dest, reflect.ValueOf(v))
+	default:
+		sqlVal := reflect.ValueOf(v)
+		return core.Assign(dest, sqlVal)
 	}
 
-	return err
+	return nil
+}
+
+// observeRaw passes the raw values of rows to RawValueObserver.
+func observeRaw(fds []pgproto3.FieldDescription, rows PgxRows) {
+	rr, ok := rows.(interface{ RawValues() [][]byte })
+	if !ok {
+		return
+	}
+	raw := rr.RawValues()
+	for i := 0; i < len(fds) && i < len(raw); i++ {
+		RawValueObserver(string(fds[i].Name), fds[i].DataTypeOID, raw[i])
+	}
+}
+
+// nameMatcher returns the matching function to use
+func nameMatcher() NameMatcherFnc {
+	if DefaultNameMatcher == nil {
+		return core.MatchFold
+	}
+	return DefaultNameMatcher
+}
+
+// matchColumns returns the name of the matching struct field for every column in fds, see core.Match.
+func matchColumns(structFields []string, fds []pgproto3.FieldDescription, matchFnc NameMatcherFnc, mapping map[string]string) []string {
+	return core.Match(structFields, columns(fds), core.MatcherFnc(matchFnc), mapping)
 }
 
-func assign(dest, src reflect.Value) (err error) {
+// columns returns the descriptions of the columns in fds for the core.
+func columns(fds []pgproto3.FieldDescription) []core.Column {
+	cols := make([]core.Column, len(fds))
+	for i, fd := range fds {
+		cols[i] = core.Column{Name: string(fd.Name), TypeOID: fd.DataTypeOID, Format: fd.Format}
+	}
+	return cols
+}
+
+// panicError is a recovered panic, assignField reports it in the ScanError.
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrInvalidDestination, e.value)
+}
+
+func (e *panicError) Unwrap() error {
+	return ErrInvalidDestination
+}
+
+// setPgtype calls pv.Set.
+// Some pgtype values panic on unexpected input, that is turned into a *panicError.
+func setPgtype(pv pgtype.Value, v interface{}) (err error) {
+	// Set compares v w/ v.Get(), which panics for uncomparable values like arrays
+	if _, ok := v.(interface{ Get() interface{} }); ok && !reflect.TypeOf(v).Comparable() {
+		return fmt.Errorf("%w: %T into %T", ErrInvalidDestination, v, pv)
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
-			err = ErrInvalidDestination
+			err = &panicError{value: r, stack: debug.Stack()}
 		}
 	}()
-	dest.Set(src)
+	return pv.Set(v)
+}
+
+// scan calls sc.Scan, turning panics into errors like setPgtype.
+func scan(sc sql.Scanner, v interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+	return sc.Scan(v)
+}
+
+// assignJSON stores the decoded JSON value v in dest.
+// Byte slices get the encoded document, everything else is unmarshaled into.
+func assignJSON(dest reflect.Value, v interface{}) error {
+	if v == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if isBytes(dest) {
+		dest.SetBytes(b)
+		return nil
+	}
+	// unmarshal into a fresh value, so nothing from the previous content remains
+	nv := reflect.New(dest.Type())
+	err = json.Unmarshal(b, nv.Interface())
+	if err != nil {
+		return err
+	}
+	dest.Set(nv.Elem())
 	return nil
 }
 
-func defaultNameMatcher(fieldName, resultName string) bool {
-	// empty  field name or result name always fails
-	if len(fieldName) < 1 || len(resultName) < 1 {
+func isJSON(oid uint32) bool {
+	return oid == pgtype.JSONOID || oid == pgtype.JSONBOID
+}
+
+// isBytes checks for []byte or a type based on it, like json.RawMessage
+func isBytes(v reflect.Value) bool {
+	t := v.Type()
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// isNullable checks if dest can hold NULL: a pointer, a Null, a NullAssigner, a pgtype value or a sql.Scanner.
+func isNullable(dest reflect.Value) bool {
+	if dest.Kind() == reflect.Ptr {
+		return true
+	}
+	if !dest.CanAddr() {
 		return false
 	}
-	// see if the names are equal
-	return strings.EqualFold(fieldName, resultName)
+	switch dest.Addr().Interface().(type) {
+	case nullSetter, NullAssigner, pgtype.Value, sql.Scanner:
+		return true
+	}
+	return false
 }
 
-// helper to recursively collect all field names from the given struct
-func getFields(r reflect.Type, m *[]string) {
-	for i := 0; i < r.NumField(); i++ {
-		field := r.Field(i)
-		if !field.Anonymous && !field.IsExported() {
-			continue
+// isStructLike checks for a struct or a pointer to a struct
+func isStructLike(v reflect.Value) bool {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// isStructSlice checks for a slice of structs or of pointers to structs, like []Child or []*Child
+func isStructSlice(v reflect.Value) bool {
+	t := v.Type()
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	t = t.Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// copyBytea copies the bytes of bytea values, which share the read buffer of pgx.
+// Other values are returned unchanged.
+func copyBytea(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		if v != nil {
+			v = append([]byte{}, v...)
+		}
+		return v
+	case pgtype.Bytea:
+		if v.Bytes != nil {
+			v.Bytes = append([]byte{}, v.Bytes...)
 		}
-		switch field.Type.Kind() {
-		case reflect.Struct:
-			getFields(field.Type, m)
-		default:
-			*m = append(*m, field.Name)
+		return v
+	case pgtype.ByteaArray:
+		elems := make([]pgtype.Bytea, len(v.Elements))
+		for i, e := range v.Elements {
+			elems[i] = copyBytea(e).(pgtype.Bytea)
 		}
+		v.Elements = elems
+		return v
+	}
+	return v
+}
+
+// setSlice assigns the slice s to dest.
+// The elements are converted if dest has a named element type, like []UserID.
+func setSlice(dest, s reflect.Value) {
+	if s.Type().AssignableTo(dest.Type()) {
+		dest.Set(s)
+		return
+	}
+	et := dest.Type().Elem()
+	res := reflect.MakeSlice(dest.Type(), s.Len(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		res.Index(i).Set(s.Index(i).Convert(et))
+	}
+	dest.Set(res)
+}
+
+// isSimpleArray checks for a 1 dimensional array whose header matches the number of elements.
+// Empty arrays have no dimensions in Postgres.
+func isSimpleArray(dims []pgtype.ArrayDimension, n int) bool {
+	if len(dims) == 0 {
+		return n == 0
+	}
+	return len(dims) == 1 && int(dims[0].Length) == n
+}
+
+// assignStringSlice assigns the n elements of a text array to dest, a slice of a string kind like []Tag.
+func assignStringSlice(dest reflect.Value, dims []pgtype.ArrayDimension, n int, elem func(i int) string) error {
+	if !isStringSlice(dest) {
+		return ErrInvalidDestination
+	}
+	if !isSimpleArray(dims, n) {
+		return ErrNotSimpleSlice
 	}
+	res := sliceFor[string](dest, n)
+	for i := 0; i < n; i++ {
+		res[i] = elem(i)
+	}
+	setSlice(dest, reflect.ValueOf(res))
+	return nil
 }
 
 func isStringSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
 	e := v.Type().Elem()
 	return e.Kind() == reflect.String
 }
 
+func isBoolSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	e := v.Type().Elem()
+	return e.Kind() == reflect.Bool
+}
+
+func isTimeSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem() == timeType
+}
+
+func isUUIDSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	e := v.Type().Elem()
+	return e.Kind() == reflect.Array && e.Len() == 16 && e.Elem().Kind() == reflect.Uint8
+}
+
 func isBytesSlice(v reflect.Value) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
 	e := v.Type().Elem()
 	if e.Kind() != reflect.Slice {
 		return false
@@ -325,10 +1166,23 @@ func isIntSize(t reflect.Type, sz int) bool {
 }
 
 func isIntSlice(v reflect.Value, sz int) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
 	e := v.Type().Elem()
 	return isIntSize(e, sz)
 }
 
+// isWideIntSlice checks for a slice of integers larger than sz bytes.
+// Smaller integers can be widened to them w/o loss.
+func isWideIntSlice(v reflect.Value, sz int) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
+	e := v.Type().Elem()
+	return (isIntSize(e, 4) || isIntSize(e, 8)) && int(e.Size()) > sz
+}
+
 func isFloatSize(t reflect.Type, sz int) bool {
 	// first check for valid int type
 	// no need for uint, Postgres does not have uints.
@@ -342,6 +1196,9 @@ func isFloatSize(t reflect.Type, sz int) bool {
 }
 
 func isFloatSlice(v reflect.Value, sz int) bool {
+	if v.Kind() != reflect.Slice {
+		return false
+	}
 	e := v.Type().Elem()
 	return isFloatSize(e, sz)
 }
@@ -1,9 +1,13 @@
 package pgxscan_test
 
 import (
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/guidog/pgxscan"
 	"github.com/jackc/pgproto3/v2"
@@ -120,6 +124,14 @@ func mkTestRows() testRows {
 	return ret
 }
 
+// helper to create rows with a single column
+func mkColumnRows(name string, oid uint32, val interface{}) testRows {
+	return testRows{
+		fds:  []pgproto3.FieldDescription{{Name: []byte(name), DataTypeOID: oid}},
+		vals: []interface{}{val},
+	}
+}
+
 func TestReadStruct(t *testing.T) {
 
 	rows := mkTestRows()
@@ -365,6 +377,385 @@ func TestReadStructInvalidTypes(t *testing.T) {
 	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
 		t.Errorf("failed to detect invalid destination type, error: %v", err)
 	}
+
+	// arrays into non-slice fields
+	var destG = struct {
+		A  string
+		Xa int32
+	}{}
+	err = pgxscan.ReadStruct(&destG, rows)
+	var serr *pgxscan.ScanError
+	if !errors.As(err, &serr) || serr.Panic != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect invalid destination type, error: %v", err)
+	}
+}
+
+func TestReadStructJSONRaw(t *testing.T) {
+	rows := mkColumnRows("doc", pgtype.JSONBOID, map[string]interface{}{"a": float64(1), "b": "x"})
+
+	var dest struct {
+		Doc json.RawMessage
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dest.Doc) != `{"a":1,"b":"x"}` {
+		t.Errorf("value mismatch for field Doc: %s", dest.Doc)
+	}
+
+	var destB struct {
+		Doc []byte
+	}
+	err = pgxscan.ReadStruct(&destB, mkColumnRows("doc", pgtype.JSONOID, []interface{}{"x", true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(destB.Doc) != `["x",true]` {
+		t.Errorf("value mismatch for field Doc: %s", destB.Doc)
+	}
+
+	// NULL leaves a nil slice
+	destB.Doc = []byte("old")
+	err = pgxscan.ReadStruct(&destB, mkColumnRows("doc", pgtype.JSONOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destB.Doc != nil {
+		t.Errorf("NULL json not assigned as nil: %s", destB.Doc)
+	}
+}
+
+func TestReadStructJSONStruct(t *testing.T) {
+	type meta struct {
+		Name string `json:"name"`
+		Tags []string
+	}
+	doc := map[string]interface{}{"name": "x", "Tags": []interface{}{"a", "b"}}
+	want := meta{Name: "x", Tags: []string{"a", "b"}}
+
+	var dest struct {
+		Meta  meta
+		Other *meta
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("meta"), DataTypeOID: pgtype.JSONBOID},
+			{Name: []byte("other"), DataTypeOID: pgtype.JSONOID},
+		},
+		vals: []interface{}{doc, doc},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Meta, want) {
+		t.Errorf("value mismatch for field Meta: %+v", dest.Meta)
+	}
+	if dest.Other == nil || !reflect.DeepEqual(*dest.Other, want) {
+		t.Errorf("value mismatch for field Other: %+v", dest.Other)
+	}
+
+	// NULL resets the pointer
+	rows.vals = []interface{}{doc, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Other != nil {
+		t.Errorf("NULL json not assigned as nil: %+v", dest.Other)
+	}
+
+	// JSON not matching the struct
+	rows.vals = []interface{}{"text", nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err == nil {
+		t.Error("invalid JSON document not detected")
+	}
+}
+
+func TestReadStructJSONStructSlice(t *testing.T) {
+	type child struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	// as returned by json_agg(children.*)
+	doc := []interface{}{
+		map[string]interface{}{"id": float64(1), "name": "a"},
+		map[string]interface{}{"id": float64(2), "name": "b"},
+	}
+	want := []child{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+
+	var dest struct {
+		Children  []child
+		ChildrenP []*child
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("children"), DataTypeOID: pgtype.JSONOID},
+			{Name: []byte("childrenp"), DataTypeOID: pgtype.JSONBOID},
+		},
+		vals: []interface{}{doc, doc},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest.Children, want) {
+		t.Errorf("value mismatch for field Children: %+v", dest.Children)
+	}
+	if len(dest.ChildrenP) != 2 || !reflect.DeepEqual(*dest.ChildrenP[1], want[1]) {
+		t.Errorf("value mismatch for field ChildrenP: %+v", dest.ChildrenP)
+	}
+
+	// json_agg over no rows is NULL
+	rows.vals = []interface{}{nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Children != nil || dest.ChildrenP != nil {
+		t.Errorf("NULL json not assigned as nil: %+v", dest)
+	}
+}
+
+func TestReadStructCockroachDBMode(t *testing.T) {
+	pgxscan.CockroachDBMode = true
+	defer func() { pgxscan.CockroachDBMode = false }()
+
+	var dest struct {
+		A int32
+		B int16
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("a")},
+			{Name: []byte("b")},
+		},
+		vals: []interface{}{int64(2135533321), int64(-5)},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.A != 2135533321 || dest.B != -5 {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	rows.vals[1] = int64(40000)
+	err = pgxscan.ReadStruct(&dest, rows)
+	if !errors.Is(err, pgxscan.ErrOutOfRange) {
+		t.Errorf("overflow not detected, error: %v", err)
+	}
+}
+
+func TestReadStructPointers(t *testing.T) {
+	ts := time.Date(2021, 8, 17, 12, 0, 0, 0, time.UTC)
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("s")},
+			{Name: []byte("i")},
+			{Name: []byte("f")},
+			{Name: []byte("ts")},
+		},
+		vals: []interface{}{"xy", int64(7), float64(0.5), ts},
+	}
+
+	var dest struct {
+		S  *string
+		I  *int64
+		F  *float64
+		Ts *time.Time
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S == nil || *dest.S != "xy" {
+		t.Error("value mismatch for field S")
+	}
+	if dest.I == nil || *dest.I != 7 {
+		t.Error("value mismatch for field I")
+	}
+	if dest.F == nil || *dest.F != 0.5 {
+		t.Error("value mismatch for field F")
+	}
+	if dest.Ts == nil || !dest.Ts.Equal(ts) {
+		t.Error("value mismatch for field Ts")
+	}
+
+	// NULL values are assigned as nil, the old targets are untouched
+	old := dest.S
+	rows.vals = []interface{}{nil, nil, nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S != nil || dest.I != nil || dest.F != nil || dest.Ts != nil {
+		t.Errorf("NULL not assigned as nil: %+v", dest)
+	}
+	if *old != "xy" {
+		t.Error("previous target modified")
+	}
+
+	// NULL into a non-pointer
+	var destB struct {
+		S string
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("NULL for non-pointer not detected, error: %v", err)
+	}
+
+	// type mismatch behind the pointer
+	var destC struct {
+		S *int64
+	}
+	rows.vals = []interface{}{"xy", nil, nil, nil}
+	err = pgxscan.ReadStruct(&destC, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect invalid destination type, error: %v", err)
+	}
+}
+
+func TestReadStructSQLNull(t *testing.T) {
+	ts := time.Date(2021, 8, 17, 12, 0, 0, 0, time.UTC)
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("s")},
+			{Name: []byte("i64")},
+			{Name: []byte("i32")},
+			{Name: []byte("f")},
+			{Name: []byte("b")},
+			{Name: []byte("ts")},
+		},
+		vals: []interface{}{"xy", int64(7), int32(8), float64(0.5), true, ts},
+	}
+
+	var dest struct {
+		S   sql.NullString
+		I64 sql.NullInt64
+		I32 sql.NullInt32
+		F   sql.NullFloat64
+		B   sql.NullBool
+		Ts  sql.NullTime
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dest
+	want.S = sql.NullString{String: "xy", Valid: true}
+	want.I64 = sql.NullInt64{Int64: 7, Valid: true}
+	want.I32 = sql.NullInt32{Int32: 8, Valid: true}
+	want.F = sql.NullFloat64{Float64: 0.5, Valid: true}
+	want.B = sql.NullBool{Bool: true, Valid: true}
+	want.Ts = sql.NullTime{Time: ts, Valid: true}
+	if !reflect.DeepEqual(dest, want) {
+		t.Errorf("value mismatch\n%+v\n%+v", dest, want)
+	}
+
+	rows.vals = []interface{}{nil, nil, nil, nil, nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S.Valid || dest.I64.Valid || dest.I32.Valid || dest.F.Valid || dest.B.Valid || dest.Ts.Valid {
+		t.Errorf("NULL not detected: %+v", dest)
+	}
+}
+
+func TestReadStructPgtype(t *testing.T) {
+	ts := time.Date(2021, 8, 17, 12, 0, 0, 0, time.UTC)
+	num := pgtype.Numeric{}
+	num.Set("12.5")
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("s")},
+			{Name: []byte("i")},
+			{Name: []byte("n")},
+			{Name: []byte("ts")},
+		},
+		vals: []interface{}{"xy", int64(7), num, ts},
+	}
+
+	var dest struct {
+		S  pgtype.Text
+		I  pgtype.Int8
+		N  pgtype.Numeric
+		Ts pgtype.Timestamptz
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S.Status != pgtype.Present || dest.S.String != "xy" {
+		t.Errorf("value mismatch for field S: %+v", dest.S)
+	}
+	if dest.I.Status != pgtype.Present || dest.I.Int != 7 {
+		t.Errorf("value mismatch for field I: %+v", dest.I)
+	}
+	if !reflect.DeepEqual(dest.N, num) {
+		t.Errorf("value mismatch for field N: %+v", dest.N)
+	}
+	if dest.Ts.Status != pgtype.Present || !dest.Ts.Time.Equal(ts) {
+		t.Errorf("value mismatch for field Ts: %+v", dest.Ts)
+	}
+
+	rows.vals = []interface{}{nil, nil, nil, nil}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S.Status != pgtype.Null || dest.I.Status != pgtype.Null || dest.N.Status != pgtype.Null || dest.Ts.Status != pgtype.Null {
+		t.Errorf("NULL status not set: %+v", dest)
+	}
+}
+
+type testDigest [4]byte
+
+func (d *testDigest) UnmarshalBinary(b []byte) error {
+	if len(b) != len(d) {
+		return errors.New("invalid digest length")
+	}
+	copy(d[:], b)
+	return nil
+}
+
+func TestReadStructBinaryUnmarshaler(t *testing.T) {
+	var dest struct {
+		D  testDigest
+		Ts time.Time
+	}
+	ts := time.Date(2021, 8, 17, 12, 0, 0, 0, time.UTC)
+	tsb, _ := ts.MarshalBinary()
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("d"), DataTypeOID: pgtype.ByteaOID},
+			{Name: []byte("ts"), DataTypeOID: pgtype.ByteaOID},
+		},
+		vals: []interface{}{[]byte{1, 2, 3, 4}, tsb},
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.D != (testDigest{1, 2, 3, 4}) {
+		t.Errorf("value mismatch for field D: %v", dest.D)
+	}
+	if !dest.Ts.Equal(ts) {
+		t.Errorf("value mismatch for field Ts: %v", dest.Ts)
+	}
+
+	// errors from UnmarshalBinary are passed on
+	rows.vals[0] = []byte{1}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err == nil {
+		t.Error("unmarshal error not returned")
+	}
 }
 
 func BenchmarkReadStruct(b *testing.B) {
@@ -401,3 +792,274 @@ func BenchmarkReadStruct(b *testing.B) {
 	}
 
 }
+
+func TestReadStructNamedTypes(t *testing.T) {
+	type UserID int64
+	type Ratio float64
+	type Raw []byte
+	var dest struct {
+		Bigid UserID
+		R     Ratio
+		X     Raw
+	}
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != 703340046535533321 {
+		t.Errorf("value mismatch for field Bigid: %v", dest.Bigid)
+	}
+	if dest.R != -0.000001 {
+		t.Errorf("value mismatch for field R: %v", dest.R)
+	}
+	if !reflect.DeepEqual(dest.X, Raw{1, 2, 3}) {
+		t.Errorf("value mismatch for field X: %v", dest.X)
+	}
+
+	// the underlying types have to match
+	var bad struct {
+		Littleid UserID
+	}
+	err = pgxscan.ReadStruct(&bad, mkTestRows())
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}
+
+func TestReadStructNamedString(t *testing.T) {
+	type Status string
+	var dest struct {
+		S Status
+		P *Status
+	}
+	// enum values of unknown types are returned as text
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("s", 16394, "active"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S != "active" {
+		t.Errorf("value mismatch for field S: %q", dest.S)
+	}
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("p", 16394, "paused"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P == nil || *dest.P != "paused" {
+		t.Errorf("value mismatch for field P: %v", dest.P)
+	}
+}
+
+func TestReadStructTrimChar(t *testing.T) {
+	var dest struct {
+		C string
+		P *string
+	}
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("c", pgtype.BPCharOID, "ab   "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.C != "ab   " {
+		t.Errorf("value trimmed w/o TrimChar: %q", dest.C)
+	}
+
+	pgxscan.TrimChar = true
+	defer func() { pgxscan.TrimChar = false }()
+	for _, col := range []string{"c", "p"} {
+		err = pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.BPCharOID, "ab   "))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.C != "ab" || dest.P == nil || *dest.P != "ab" {
+		t.Errorf("value not trimmed: %q %v", dest.C, dest.P)
+	}
+
+	// other types keep their spaces
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("c", pgtype.TextOID, "ab   "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.C != "ab   " {
+		t.Errorf("text value trimmed: %q", dest.C)
+	}
+}
+
+func TestReadStructEmptyAsNull(t *testing.T) {
+	dest := struct {
+		S  string
+		P  *string
+		NS sql.NullString
+		T  pgtype.Text
+	}{P: new(string)}
+	fds := []pgproto3.FieldDescription{
+		{Name: []byte("s"), DataTypeOID: pgtype.TextOID},
+		{Name: []byte("p"), DataTypeOID: pgtype.TextOID},
+		{Name: []byte("ns"), DataTypeOID: pgtype.TextOID},
+		{Name: []byte("t"), DataTypeOID: pgtype.TextOID},
+	}
+	rows := testRows{fds: fds, vals: []interface{}{"", "", "", ""}}
+
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.P == nil || !dest.NS.Valid || dest.T.Status != pgtype.Present {
+		t.Errorf("empty value assigned as NULL w/o EmptyAsNull: %+v", dest)
+	}
+
+	pgxscan.EmptyAsNull = true
+	defer func() { pgxscan.EmptyAsNull = false }()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.S != "" || dest.P != nil || dest.NS.Valid || dest.T.Status != pgtype.Null {
+		t.Errorf("empty value not assigned as NULL: %+v", dest)
+	}
+}
+
+func TestReadStructInterface(t *testing.T) {
+	var dest struct {
+		Bigid  interface{}
+		String any
+		A      interface{}
+		S      fmt.Stringer
+	}
+	dest.A = "x"
+	err := pgxscan.ReadStruct(&dest, mkTestRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Bigid != int64(703340046535533321) || dest.String != "xy" {
+		t.Errorf("value mismatch: %v %v", dest.Bigid, dest.String)
+	}
+	if _, ok := dest.A.(pgtype.TextArray); !ok {
+		t.Errorf("value mismatch for field A: %T", dest.A)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("a", pgtype.TextOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.A != nil {
+		t.Errorf("NULL not assigned: %v", dest.A)
+	}
+
+	// the value has to implement the interface
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("s", pgtype.TextOID, "x"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}
+
+func TestReadStructNamedBytes(t *testing.T) {
+	type SHA256 []byte
+	type octet uint8
+	var dest struct {
+		X SHA256
+		Y []octet
+		P *SHA256
+	}
+	for _, col := range []string{"x", "y", "p"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, pgtype.ByteaOID, []byte{1, 2, 3}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(dest.X, SHA256{1, 2, 3}) || !reflect.DeepEqual(dest.Y, []octet{1, 2, 3}) {
+		t.Errorf("value mismatch: %v %v", dest.X, dest.Y)
+	}
+	if dest.P == nil || !reflect.DeepEqual(*dest.P, SHA256{1, 2, 3}) {
+		t.Errorf("value mismatch for field P: %v", dest.P)
+	}
+
+	var bad struct {
+		X []int8
+	}
+	err := pgxscan.ReadStruct(&bad, mkColumnRows("x", pgtype.ByteaOID, []byte{1}))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}
+
+func TestReadStructZeroCopyBytes(t *testing.T) {
+	buf := []byte("abc")
+	arr := pgtype.ByteaArray{
+		Elements:   []pgtype.Bytea{{Bytes: buf, Status: pgtype.Present}},
+		Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("b"), DataTypeOID: pgtype.ByteaOID},
+			{Name: []byte("bs"), DataTypeOID: pgtype.ByteaArrayOID},
+		},
+		vals: []interface{}{buf, arr},
+	}
+
+	var dest struct {
+		B  []byte
+		BS [][]byte
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &dest.B[0] == &buf[0] || &dest.BS[0][0] == &buf[0] {
+		t.Error("bytes not copied")
+	}
+
+	pgxscan.ZeroCopyBytes = true
+	defer func() { pgxscan.ZeroCopyBytes = false }()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &dest.B[0] != &buf[0] || &dest.BS[0][0] != &buf[0] {
+		t.Error("bytes copied w/ ZeroCopyBytes")
+	}
+}
+
+func TestReadStructInterfaceBytes(t *testing.T) {
+	buf := []byte{1, 2, 3}
+	arr := pgtype.ByteaArray{
+		Elements:   []pgtype.Bytea{{Bytes: buf, Status: pgtype.Present}},
+		Dimensions: []pgtype.ArrayDimension{{Length: 1, LowerBound: 1}},
+		Status:     pgtype.Present,
+	}
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("b"), DataTypeOID: pgtype.ByteaOID},
+			{Name: []byte("bs"), DataTypeOID: pgtype.ByteaArrayOID},
+		},
+		vals: []interface{}{buf, arr},
+	}
+
+	var dest struct {
+		B  interface{}
+		BS interface{}
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pgx reuses the buffer for the next record
+	buf[0] = 9
+	if !reflect.DeepEqual(dest.B, []byte{1, 2, 3}) {
+		t.Errorf("value of field B changed w/ the buffer: %v", dest.B)
+	}
+	if bs, ok := dest.BS.(pgtype.ByteaArray); !ok || !reflect.DeepEqual(bs.Elements[0].Bytes, []byte{1, 2, 3}) {
+		t.Errorf("value of field BS changed w/ the buffer: %v", dest.BS)
+	}
+
+	pgxscan.ZeroCopyBytes = true
+	defer func() { pgxscan.ZeroCopyBytes = false }()
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := dest.B.([]byte); !ok || &b[0] != &buf[0] {
+		t.Error("bytes copied w/ ZeroCopyBytes")
+	}
+}
@@ -0,0 +1,128 @@
+package pgxscan
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgtype"
+)
+
+// CollectDecodeStats enables statistics on the columns read by ReadStruct, per struct type.
+// They show which columns account for most of the scan cost, e.g. a big jsonb column
+// that should be left out or read into a Lazy field, see DecodeStatsOf.
+//
+// Sizes are counted for rows providing the raw values, like pgx.Rows.
+// Decode times are measured for rows decoded by pgxscan, like rows passed through WithConnInfo,
+// otherwise pgx has decoded the whole record before.
+// Columns read into Lazy fields are not decoded while scanning and not counted.
+var CollectDecodeStats = false
+
+// ColumnStats holds the statistics of a column, see CollectDecodeStats.
+type ColumnStats struct {
+	// Column is the name of the column.
+	Column string
+	// Values is the number of values read.
+	Values int64
+	// Bytes is the size of the raw values.
+	Bytes int64
+	// Time is the time spent decoding the values, 0 if it could not be measured.
+	Time time.Duration
+}
+
+var (
+	decodeStatsMu sync.Mutex
+	decodeStats   = map[reflect.Type]map[string]*ColumnStats{}
+)
+
+// DecodeStatsOf returns the statistics of the columns read into T,
+// the most expensive column first: ordered by time and size.
+func DecodeStatsOf[T any]() []ColumnStats {
+	decodeStatsMu.Lock()
+	defer decodeStatsMu.Unlock()
+
+	cols := decodeStats[reflect.TypeOf((*T)(nil)).Elem()]
+	res := make([]ColumnStats, 0, len(cols))
+	for _, cs := range cols {
+		res = append(res, *cs)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Time != res[j].Time {
+			return res[i].Time > res[j].Time
+		}
+		if res[i].Bytes != res[j].Bytes {
+			return res[i].Bytes > res[j].Bytes
+		}
+		return res[i].Column < res[j].Column
+	})
+	return res
+}
+
+// ResetDecodeStats drops all statistics.
+func ResetDecodeStats() {
+	decodeStatsMu.Lock()
+	defer decodeStatsMu.Unlock()
+
+	decodeStats = map[reflect.Type]map[string]*ColumnStats{}
+}
+
+// statsRecorder adds the columns of a record to the statistics of a struct type.
+type statsRecorder struct {
+	t reflect.Type
+}
+
+// statsFor returns the recorder for the struct type t, nil w/o CollectDecodeStats.
+func statsFor(t reflect.Type) *statsRecorder {
+	if !CollectDecodeStats {
+		return nil
+	}
+	return &statsRecorder{t: t}
+}
+
+// add counts a value of size n of the column col, decoded in d.
+func (r *statsRecorder) add(col string, n int, d time.Duration) {
+	decodeStatsMu.Lock()
+	defer decodeStatsMu.Unlock()
+
+	cols := decodeStats[r.t]
+	if cols == nil {
+		cols = map[string]*ColumnStats{}
+		decodeStats[r.t] = cols
+	}
+	cs := cols[col]
+	if cs == nil {
+		cs = &ColumnStats{Column: col}
+		cols[col] = cs
+	}
+	cs.Values++
+	cs.Bytes += int64(n)
+	cs.Time += d
+}
+
+// addSizes counts the raw values of the record in rows, if rows provide them.
+// The columns marked in lazy are skipped.
+func (r *statsRecorder) addSizes(rows PgxRows, lazy []bool) {
+	rr, ok := rows.(interface{ RawValues() [][]byte })
+	if !ok {
+		return
+	}
+	fds := rows.FieldDescriptions()
+	raw := rr.RawValues()
+	for i := 0; i < len(fds) && i < len(raw); i++ {
+		if lazy == nil || !lazy[i] {
+			r.add(string(fds[i].Name), len(raw[i]), 0)
+		}
+	}
+}
+
+// decode decodes the raw value b of the column i like decodeRaw and counts it.
+func (r *statsRecorder) decode(ci *pgtype.ConnInfo, rows PgxRows, i int, b []byte) (interface{}, error) {
+	fd := rows.FieldDescriptions()[i]
+	start := time.Now()
+	v, err := decodeRaw(ci, fd.DataTypeOID, fd.Format, b)
+	if r != nil {
+		r.add(string(fd.Name), len(b), time.Since(start))
+	}
+	return v, err
+}
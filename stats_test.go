@@ -0,0 +1,104 @@
+package pgxscan_test
+
+import (
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgtype"
+)
+
+type statsRecord struct {
+	ID  int64
+	Doc map[string]interface{}
+}
+
+func TestDecodeStats(t *testing.T) {
+	pgxscan.CollectDecodeStats = true
+	defer func() {
+		pgxscan.CollectDecodeStats = false
+		pgxscan.ResetDecodeStats()
+	}()
+
+	cr := &pgxscan.CapturedResult{
+		Columns: []pgxscan.CapturedColumn{
+			{Name: "id", OID: pgtype.Int8OID},
+			{Name: "doc", OID: pgtype.JSONBOID},
+		},
+		Records: [][][]byte{
+			{[]byte("7"), []byte(`{"a":"x","b":[1,2,3]}`)},
+			{[]byte("8"), []byte(`{"a":"y"}`)},
+		},
+	}
+	rows := cr.Replay(nil)
+	for rows.Next() {
+		var dest statsRecord
+		if err := pgxscan.ReadStruct(&dest, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := pgxscan.DecodeStatsOf[statsRecord]()
+	if len(stats) != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	var doc pgxscan.ColumnStats
+	for _, cs := range stats {
+		if cs.Column == "doc" {
+			doc = cs
+		}
+	}
+	if doc.Values != 2 || doc.Bytes != 30 || doc.Time <= 0 {
+		t.Errorf("unexpected stats for doc: %+v", doc)
+	}
+
+	// other types are counted separately
+	if stats := pgxscan.DecodeStatsOf[struct{ ID int64 }](); len(stats) != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	pgxscan.ResetDecodeStats()
+	if stats := pgxscan.DecodeStatsOf[statsRecord](); len(stats) != 0 {
+		t.Errorf("stats not reset: %+v", stats)
+	}
+}
+
+func TestDecodeStatsSizes(t *testing.T) {
+	pgxscan.CollectDecodeStats = true
+	defer func() {
+		pgxscan.CollectDecodeStats = false
+		pgxscan.ResetDecodeStats()
+	}()
+
+	// decoded by pgx, only the sizes are known
+	var dest struct {
+		ID   int64
+		Name string
+	}
+	rows := &fakeRows{testRows: mkPairRows(), n: 1}
+	rows.Next()
+	if err := pgxscan.ReadStruct(&dest, rows); err != nil {
+		t.Fatal(err)
+	}
+	stats := pgxscan.DecodeStatsOf[struct {
+		ID   int64
+		Name string
+	}]()
+	if len(stats) != 2 || stats[0].Values != 1 || stats[0].Time != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// nothing w/o CollectDecodeStats
+	pgxscan.CollectDecodeStats = false
+	pgxscan.ResetDecodeStats()
+	rows = &fakeRows{testRows: mkPairRows(), n: 1}
+	rows.Next()
+	if err := pgxscan.ReadStruct(&dest, rows); err != nil {
+		t.Fatal(err)
+	}
+	if stats := pgxscan.DecodeStatsOf[struct {
+		ID   int64
+		Name string
+	}](); len(stats) != 0 {
+		t.Errorf("stats collected w/o CollectDecodeStats: %+v", stats)
+	}
+}
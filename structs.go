@@ -0,0 +1,71 @@
+package pgxscan
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrNotSlice is returned by ReadStructs if the destination is not a pointer to a slice of structs.
+var ErrNotSlice = errors.New("arg not a pointer to a slice of structs")
+
+// ReadStructs reads all remaining records in rows into dest and closes rows.
+//
+// dest has to be a pointer to a slice of structs or of pointers to structs, like *[]User or *[]*User.
+// One struct per record is filled by ReadStruct and appended to the slice.
+// dest is only changed if all records were read w/o error, or w/ PartialResults
+// if a timeout ended the result early.
+func ReadStructs(dest interface{}, rows pgx.Rows) error {
+	defer rows.Close()
+
+	sv, err := structSliceOf(dest)
+	if err != nil {
+		return err
+	}
+
+	et := sv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+
+	res := sv
+	var st sizeTracker
+	for rows.Next() {
+		ev := reflect.New(et)
+		err := readStruct(ev.Interface(), rows, &st)
+		if err != nil {
+			return err
+		}
+		if !isPtr {
+			ev = ev.Elem()
+		}
+		res = reflect.Append(res, ev)
+	}
+	err = finishCollect(rows)
+	if err != nil && !errors.Is(err, ErrPartialResult) {
+		return err
+	}
+	sv.Set(res)
+	return err
+}
+
+// structSliceOf returns the slice dest points to, checking it holds structs or pointers to structs.
+func structSliceOf(dest interface{}) (reflect.Value, error) {
+	if dest == nil {
+		return reflect.Value{}, ErrDestNil
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr {
+		return reflect.Value{}, ErrNotPointer
+	}
+	if dv.IsNil() {
+		return reflect.Value{}, ErrDestNil
+	}
+	sv := dv.Elem()
+	if !isStructSlice(sv) {
+		return reflect.Value{}, ErrNotSlice
+	}
+	return sv, nil
+}
@@ -0,0 +1,63 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+func TestReadStructs(t *testing.T) {
+	type pair struct {
+		ID   int64
+		Name string
+	}
+
+	rows := &fakeRows{testRows: mkPairRows(), n: 3}
+	var dest []pair
+	err := pgxscan.ReadStructs(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dest) != 3 || dest[2] != (pair{ID: 7, Name: "x"}) {
+		t.Errorf("unexpected structs: %v", dest)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+
+	// records are appended
+	destP := []*pair{{ID: 1}}
+	err = pgxscan.ReadStructs(&destP, &fakeRows{testRows: mkPairRows(), n: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destP) != 3 || destP[0].ID != 1 || *destP[2] != (pair{ID: 7, Name: "x"}) {
+		t.Errorf("unexpected structs: %v", destP)
+	}
+
+	// no records
+	var destE []pair
+	err = pgxscan.ReadStructs(&destE, &fakeRows{testRows: mkPairRows()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(destE) != 0 {
+		t.Errorf("unexpected structs: %v", destE)
+	}
+
+	rows = &fakeRows{testRows: mkPairRows(), n: 1}
+	var ids []int64
+	err = pgxscan.ReadStructs(&ids, rows)
+	if !errors.Is(err, pgxscan.ErrNotSlice) {
+		t.Errorf("failed to detect invalid destination: %v", err)
+	}
+	if !rows.closed {
+		t.Error("rows not closed on error")
+	}
+
+	err = pgxscan.ReadStructs(dest, &fakeRows{testRows: mkPairRows(), n: 1})
+	if !errors.Is(err, pgxscan.ErrNotPointer) {
+		t.Errorf("failed to detect non-pointer destination: %v", err)
+	}
+}
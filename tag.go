@@ -0,0 +1,208 @@
+package pgxscan
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+// fieldTag holds the parsed db tag of a struct field.
+type fieldTag struct {
+	Column string // column the field is bound to, "" for name matching
+	Skip   bool   // tag "-", the field is never filled
+	Layout string // layout for text columns parsed into time.Time
+
+	NullValue *string // text of the value assigned for NULL, nil if not set
+}
+
+// tagCache holds the tags of struct types, keyed by reflect.Type.
+var tagCache sync.Map
+
+// TagReaderFnc returns the column a struct field is bound to by a tag of another library.
+// It returns "" if the field has no such tag, "-" excludes the field.
+type TagReaderFnc func(tag reflect.StructTag) string
+
+var (
+	tagReadersMu sync.RWMutex
+	tagReaders   []TagReaderFnc
+)
+
+// SetTagReaders sets the functions reading column names from tags of other libraries,
+// so models annotated for them can be used unchanged:
+//
+//	pgxscan.SetTagReaders(pgxscan.GormTag, pgxscan.BunTag)
+//
+// The readers are asked in order for fields w/o db tag, the first column returned is used.
+// sqlx uses db tags already, so its models need no reader.
+// Calling SetTagReaders w/o arguments removes all readers.
+func SetTagReaders(readers ...TagReaderFnc) {
+	tagReadersMu.Lock()
+	defer tagReadersMu.Unlock()
+
+	tagReaders = append([]TagReaderFnc(nil), readers...)
+	tagCache.Range(func(k, _ interface{}) bool {
+		tagCache.Delete(k)
+		return true
+	})
+}
+
+// GormTag reads the column from a gorm tag like `gorm:"column:user_id;not null"`.
+func GormTag(tag reflect.StructTag) string {
+	s, ok := tag.Lookup("gorm")
+	if !ok {
+		return ""
+	}
+	for _, opt := range strings.Split(s, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(opt), ":")
+		switch {
+		case k == "-":
+			return "-"
+		case strings.EqualFold(k, "column"):
+			return v
+		}
+	}
+	return ""
+}
+
+// BunTag reads the column from a bun tag like `bun:"user_id,pk"`.
+func BunTag(tag reflect.StructTag) string {
+	s, ok := tag.Lookup("bun")
+	if !ok {
+		return ""
+	}
+	col, _, _ := strings.Cut(s, ",")
+	if strings.Contains(col, ":") {
+		// options like table:users of bun.BaseModel
+		return ""
+	}
+	return col
+}
+
+// readTag returns the column for the tag from the registered tag readers.
+func readTag(tag reflect.StructTag) string {
+	tagReadersMu.RLock()
+	defer tagReadersMu.RUnlock()
+
+	for _, r := range tagReaders {
+		if col := r(tag); col != "" {
+			return col
+		}
+	}
+	return ""
+}
+
+// parseTag parses a tag like `db:"created,layout=2006-01-02 15:04"` or `db:"age,nullvalue=-1"`.
+// The layout takes the rest of the tag, so it may contain commas.
+func parseTag(s string) fieldTag {
+	if s == "-" {
+		return fieldTag{Skip: true}
+	}
+
+	var tag fieldTag
+	tag.Column, s, _ = strings.Cut(s, ",")
+	for s != "" {
+		if strings.HasPrefix(s, "layout=") {
+			tag.Layout = strings.TrimPrefix(s, "layout=")
+			break
+		}
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		if k, v, ok := strings.Cut(opt, "="); ok && k == "nullvalue" {
+			tag.NullValue = &v
+		}
+	}
+	return tag
+}
+
+// tagsOf returns the db tags of the fields of the struct type t, keyed by field name.
+// Fields w/o db tag get the column from the tag readers. Fields w/o tag are not included.
+func tagsOf(t reflect.Type) map[string]fieldTag {
+	if tags, ok := tagCache.Load(t); ok {
+		return tags.(map[string]fieldTag)
+	}
+
+	names := core.Fields(t)
+
+	tags := map[string]fieldTag{}
+	for _, name := range names {
+		f, ok := t.FieldByName(name)
+		if !ok {
+			continue
+		}
+		if s, ok := f.Tag.Lookup("db"); ok {
+			tags[name] = parseTag(s)
+			continue
+		}
+		switch col := readTag(f.Tag); col {
+		case "":
+		case "-":
+			tags[name] = fieldTag{Skip: true}
+		default:
+			tags[name] = fieldTag{Column: col}
+		}
+	}
+
+	tagCache.Store(t, tags)
+	return tags
+}
+
+// columnMapping returns the field to column bindings for the struct type t.
+// Registered mappings take precedence over db tags.
+func columnMapping(t reflect.Type) map[string]string {
+	mapping := mappingFor(t)
+	tags := tagsOf(t)
+	if len(tags) == 0 {
+		return mapping
+	}
+
+	m := make(map[string]string, len(tags)+len(mapping))
+	for field, tag := range tags {
+		switch {
+		case tag.Skip:
+			m[field] = "" // matches no column
+		case tag.Column != "":
+			m[field] = tag.Column
+		}
+	}
+	for field, col := range mapping {
+		m[field] = col
+	}
+	return m
+}
+
+// assignNullValue assigns the text s of a nullvalue option to dest.
+// Strings, bools and numbers are supported.
+func assignNullValue(dest reflect.Value, s string) error {
+	switch dest.Kind() {
+	case reflect.String:
+		dest.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%w: %q is not a bool", ErrInvalidDestination, s)
+		}
+		dest.SetBool(b)
+		return nil
+	}
+	return parseNumber(dest, s)
+}
+
+// parseLayout parses the text value v into a time.Time using layout.
+// Other values are returned unchanged.
+func parseLayout(v interface{}, layout string) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q does not match layout %q", ErrInvalidDestination, s, layout)
+	}
+	return t, nil
+}
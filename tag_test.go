@@ -0,0 +1,145 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func TestReadStructTagColumn(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("user_id"), DataTypeOID: pgtype.Int8OID},
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+		},
+		vals: []interface{}{int64(42), "bob"},
+	}
+
+	var dest struct {
+		ID   int64  `db:"user_id"`
+		Name string `db:"-"`
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.ID != 42 {
+		t.Errorf("value mismatch for field ID: %d", dest.ID)
+	}
+	if dest.Name != "" {
+		t.Errorf("field Name w/ tag - was filled: %q", dest.Name)
+	}
+}
+
+func TestReadStructTagLayout(t *testing.T) {
+	rows := mkColumnRows("created", pgtype.TextOID, "2021-03-04 05:06")
+
+	var dest struct {
+		Created time.Time `db:"created,layout=2006-01-02 15:04"`
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 3, 4, 5, 6, 0, 0, time.UTC); !dest.Created.Equal(want) {
+		t.Errorf("value mismatch for field Created: %v", dest.Created)
+	}
+
+	var destP struct {
+		Created *time.Time `db:",layout=Jan 2, 2006"`
+	}
+	err = pgxscan.ReadStruct(&destP, mkColumnRows("created", pgtype.TextOID, "Mar 4, 2021"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destP.Created == nil || !destP.Created.Equal(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("value mismatch for field Created: %v", destP.Created)
+	}
+
+	err = pgxscan.ReadStruct(&destP, mkColumnRows("created", pgtype.TextOID, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if destP.Created != nil {
+		t.Errorf("NULL not assigned as nil: %v", destP.Created)
+	}
+
+	err = pgxscan.ReadStruct(&dest, mkColumnRows("created", pgtype.TextOID, "yesterday"))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect text not matching the layout: %v", err)
+	}
+}
+
+func TestReadStructTagReaders(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("user_id"), DataTypeOID: pgtype.Int8OID},
+			{Name: []byte("mail"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+		},
+		vals: []interface{}{int64(42), "bob@example.com", "bob"},
+	}
+
+	type model struct {
+		ID    int64  `gorm:"primaryKey;column:user_id"`
+		Email string `bun:"mail,notnull"`
+		Name  string `gorm:"-"`
+	}
+
+	var dest model
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.ID != 0 || dest.Email != "" || dest.Name != "bob" {
+		t.Errorf("tags read w/o tag readers: %+v", dest)
+	}
+
+	pgxscan.SetTagReaders(pgxscan.GormTag, pgxscan.BunTag)
+	defer pgxscan.SetTagReaders()
+
+	dest = model{}
+	err = pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.ID != 42 || dest.Email != "bob@example.com" || dest.Name != "" {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+}
+
+func TestReadStructTagNullValue(t *testing.T) {
+	rows := testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("age"), DataTypeOID: pgtype.Int4OID},
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+			{Name: []byte("score"), DataTypeOID: pgtype.Float8OID},
+		},
+		vals: []interface{}{nil, nil, float64(1.5)},
+	}
+
+	var dest struct {
+		Age   int32   `db:"age,nullvalue=-1"`
+		Name  string  `db:",nullvalue=n/a"`
+		Score float64 `db:"score,nullvalue=0"`
+	}
+	err := pgxscan.ReadStruct(&dest, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Age != -1 || dest.Name != "n/a" || dest.Score != 1.5 {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	var destB struct {
+		Age int32 `db:"age,nullvalue=none"`
+	}
+	err = pgxscan.ReadStruct(&destB, rows)
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("failed to detect invalid null value: %v", err)
+	}
+}
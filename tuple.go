@@ -0,0 +1,100 @@
+package pgxscan
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrColumnCount is returned by the tuple functions if the number of columns does not match the tuple.
+var ErrColumnCount = errors.New("number of columns does not match destination")
+
+// Tuple2 holds the values of a record w/ two columns.
+type Tuple2[A, B any] struct {
+	A A
+	B B
+}
+
+// Tuple3 holds the values of a record w/ three columns.
+type Tuple3[A, B, C any] struct {
+	A A
+	B B
+	C C
+}
+
+// ScanTuple2 returns the values of the current record in rows, which has to have two columns.
+// The values are converted like struct fields.
+func ScanTuple2[A, B any](rows PgxRows) (a A, b B, err error) {
+	err = readColumns(rows, nil, &a, &b)
+	return a, b, err
+}
+
+// ScanTuple3 returns the values of the current record in rows, which has to have three columns.
+// The values are converted like struct fields.
+func ScanTuple3[A, B, C any](rows PgxRows) (a A, b B, c C, err error) {
+	err = readColumns(rows, nil, &a, &b, &c)
+	return a, b, c, err
+}
+
+// ScanTuples2 reads all records of a two column result and closes rows.
+func ScanTuples2[A, B any](rows pgx.Rows) ([]Tuple2[A, B], error) {
+	defer rows.Close()
+
+	var tuples []Tuple2[A, B]
+	var st sizeTracker
+	for rows.Next() {
+		var t Tuple2[A, B]
+		err := readColumns(rows, &st, &t.A, &t.B)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, t)
+	}
+	return tuples, finishCollect(rows)
+}
+
+// ScanTuples3 reads all records of a three column result and closes rows.
+func ScanTuples3[A, B, C any](rows pgx.Rows) ([]Tuple3[A, B, C], error) {
+	defer rows.Close()
+
+	var tuples []Tuple3[A, B, C]
+	var st sizeTracker
+	for rows.Next() {
+		var t Tuple3[A, B, C]
+		err := readColumns(rows, &st, &t.A, &t.B, &t.C)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, t)
+	}
+	return tuples, finishCollect(rows)
+}
+
+// readColumns assigns the values of the current record to dests, one pointer per column.
+// st tracks the size of the result for the collectors, it is nil for single records.
+func readColumns(rows PgxRows, st *sizeTracker, dests ...interface{}) error {
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	fds := rows.FieldDescriptions()
+	if len(fds) != len(dests) {
+		return ErrColumnCount
+	}
+	vals, err := rows.Values()
+	if err != nil {
+		return err
+	}
+	if err := checkSize(vals, st); err != nil {
+		return err
+	}
+
+	for i, d := range dests {
+		err = assignField(reflect.ValueOf(d).Elem(), vals[i], &fds[i], "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package pgxscan_test
+
+import (
+	"testing"
+
+	"github.com/guidog/pgxscan"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgtype"
+)
+
+func mkPairRows() testRows {
+	return testRows{
+		fds: []pgproto3.FieldDescription{
+			{Name: []byte("id"), DataTypeOID: pgtype.Int8OID},
+			{Name: []byte("name"), DataTypeOID: pgtype.TextOID},
+		},
+		vals: []interface{}{int64(7), "x"},
+	}
+}
+
+func TestScanTuple(t *testing.T) {
+	id, name, err := pgxscan.ScanTuple2[int64, *string](mkPairRows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 7 || name == nil || *name != "x" {
+		t.Errorf("value mismatch: %v %v", id, name)
+	}
+
+	_, _, _, err = pgxscan.ScanTuple3[int64, string, string](mkPairRows())
+	if err != pgxscan.ErrColumnCount {
+		t.Errorf("column count mismatch not detected, error: %v", err)
+	}
+
+	_, _, err = pgxscan.ScanTuple2[string, string](mkPairRows())
+	if err == nil {
+		t.Error("invalid destination not detected")
+	}
+}
+
+func TestScanTuples(t *testing.T) {
+	rows := &fakeRows{testRows: mkPairRows(), n: 3}
+	tuples, err := pgxscan.ScanTuples2[int64, string](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tuples) != 3 || tuples[2] != (pgxscan.Tuple2[int64, string]{A: 7, B: "x"}) {
+		t.Errorf("unexpected tuples: %v", tuples)
+	}
+	if !rows.closed {
+		t.Error("rows not closed")
+	}
+}
@@ -0,0 +1,92 @@
+package pgxscan
+
+import (
+	"sync"
+)
+
+// TypeDecoderFnc is the signature for a function decoding the values of a Postgres type.
+// v is the value returned by pgx, for types unknown to pgx a string in text format
+// and a []byte in binary format. v is never nil, NULL is not passed.
+// The returned value is assigned to the struct field.
+type TypeDecoderFnc func(v interface{}) (interface{}, error)
+
+var (
+	typeDecodersMu sync.RWMutex
+	// namedDecoders are resolved to OIDs by PrepareConn
+	namedDecoders = map[string]TypeDecoderFnc{
+		"citext": decodeText,
+		"ltree":  decodeLtree,
+	}
+	oidDecoders = map[uint32]TypeDecoderFnc{
+		intervalArrayOID: decodeIntervalArray,
+	}
+)
+
+// RegisterTypeDecoder registers fnc for the Postgres type name, e.g. a type of an extension.
+//
+// The OIDs of such types are assigned when the type is created, so the name is resolved by PrepareConn,
+// which has to be called afterwards. All connections are expected to use the same database.
+// citext and ltree are registered by default, they decode into string fields in text and binary format.
+// ltree values can also be assigned to []string fields, which get the labels of the path.
+// Registering a name again replaces the function, nil removes it.
+func RegisterTypeDecoder(name string, fnc TypeDecoderFnc) {
+	typeDecodersMu.Lock()
+	defer typeDecodersMu.Unlock()
+
+	if fnc == nil {
+		delete(namedDecoders, name)
+		return
+	}
+	namedDecoders[name] = fnc
+}
+
+// RegisterOIDDecoder registers fnc for the Postgres type w/ the given OID.
+// Registering an OID again replaces the function, nil removes it.
+func RegisterOIDDecoder(oid uint32, fnc TypeDecoderFnc) {
+	typeDecodersMu.Lock()
+	defer typeDecodersMu.Unlock()
+
+	if fnc == nil {
+		delete(oidDecoders, oid)
+		return
+	}
+	oidDecoders[oid] = fnc
+}
+
+// typeDecoderNames returns the names of the registered type decoders.
+func typeDecoderNames() []string {
+	typeDecodersMu.RLock()
+	defer typeDecodersMu.RUnlock()
+
+	names := make([]string, 0, len(namedDecoders))
+	for name := range namedDecoders {
+		names = append(names, name)
+	}
+	return names
+}
+
+// resolveTypeDecoder registers the decoder for the type name under oid.
+func resolveTypeDecoder(name string, oid uint32) {
+	typeDecodersMu.Lock()
+	defer typeDecodersMu.Unlock()
+
+	if fnc, ok := namedDecoders[name]; ok {
+		oidDecoders[oid] = fnc
+	}
+}
+
+// typeDecoderFor returns the decoder for oid or nil.
+func typeDecoderFor(oid uint32) TypeDecoderFnc {
+	typeDecodersMu.RLock()
+	defer typeDecodersMu.RUnlock()
+
+	return oidDecoders[oid]
+}
+
+// decodeText returns text values as string, whatever the format.
+func decodeText(v interface{}) (interface{}, error) {
+	if b, ok := v.([]byte); ok {
+		return string(b), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,43 @@
+package pgxscan_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+func TestRegisterOIDDecoder(t *testing.T) {
+	const extOID = 16500
+	pgxscan.RegisterOIDDecoder(extOID, func(v interface{}) (interface{}, error) {
+		switch v := v.(type) {
+		case []byte:
+			return strings.ToUpper(string(v)), nil
+		case string:
+			return strings.ToUpper(v), nil
+		}
+		return nil, errors.New("unexpected value")
+	})
+	defer pgxscan.RegisterOIDDecoder(extOID, nil)
+
+	var dest struct {
+		Text string
+		Bin  string
+		Null *string
+	}
+	for col, v := range map[string]interface{}{"text": "abc", "bin": []byte("def"), "null": nil} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, extOID, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.Text != "ABC" || dest.Bin != "DEF" || dest.Null != nil {
+		t.Errorf("value mismatch: %+v", dest)
+	}
+
+	err := pgxscan.ReadStruct(&dest, mkColumnRows("text", extOID, int64(1)))
+	if err == nil || err.Error() != "field Text can't hold result text, unexpected value" {
+		t.Errorf("decoder error not returned: %v", err)
+	}
+}
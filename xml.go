@@ -0,0 +1,41 @@
+package pgxscan
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	"github.com/guidog/pgxscan/core"
+)
+
+// xmlOID is the OID of the xml type, pgtype has no support for it.
+const xmlOID = 142
+
+// assignXML assigns the xml document v to dest.
+// dest can be a string, a []byte or a type implementing xml.Unmarshaler.
+func assignXML(dest reflect.Value, v interface{}) error {
+	var doc []byte
+	switch v := v.(type) {
+	case string:
+		doc = []byte(v)
+	case []byte:
+		doc = append([]byte(nil), v...)
+	default:
+		return core.Assign(dest, reflect.ValueOf(v))
+	}
+
+	if dest.CanAddr() {
+		if _, ok := dest.Addr().Interface().(xml.Unmarshaler); ok {
+			return xml.Unmarshal(doc, dest.Addr().Interface())
+		}
+	}
+
+	switch {
+	case dest.Kind() == reflect.String:
+		dest.SetString(string(doc))
+	case isBytes(dest):
+		dest.SetBytes(doc)
+	default:
+		return ErrInvalidDestination
+	}
+	return nil
+}
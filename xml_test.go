@@ -0,0 +1,55 @@
+package pgxscan_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/guidog/pgxscan"
+)
+
+const xmlOID = 142
+
+// feed decodes itself from xml.
+type feed struct {
+	Title string
+}
+
+func (f *feed) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v struct {
+		Title string `xml:"title"`
+	}
+	err := d.DecodeElement(&v, &start)
+	f.Title = v.Title
+	return err
+}
+
+func TestReadStructXML(t *testing.T) {
+	const doc = `<feed><title>news</title></feed>`
+	var dest struct {
+		S string
+		B []byte
+		F feed
+		P *feed
+	}
+	for _, col := range []string{"s", "b", "f", "p"} {
+		err := pgxscan.ReadStruct(&dest, mkColumnRows(col, xmlOID, doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if dest.S != doc || string(dest.B) != doc {
+		t.Errorf("value mismatch: %q %q", dest.S, dest.B)
+	}
+	if dest.F.Title != "news" || dest.P == nil || dest.P.Title != "news" {
+		t.Errorf("value mismatch: %v %v", dest.F, dest.P)
+	}
+
+	var bad struct {
+		S int64
+	}
+	err := pgxscan.ReadStruct(&bad, mkColumnRows("s", xmlOID, doc))
+	if !errors.Is(err, pgxscan.ErrInvalidDestination) {
+		t.Errorf("invalid destination not detected, error: %v", err)
+	}
+}